@@ -0,0 +1,178 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// isAliasRule returns true if the rule enforces the alias of the import
+// instead of disallowing it.
+func (r *rule) isAliasRule() bool {
+	return r.alias != "" || len(r.forbiddenAliases) > 0 || r.noAlias
+}
+
+// reportAlias reports the import spec if its alias doesn't conform to the
+// rule and it's not ignored. An alias equal to the package name is an alias
+// as well. The reported problem has a suggested fix, which renames the import
+// and all of its qualified usages in f, unless the new name conflicts with
+// another declaration.
+func reportAlias(pass *analysis.Pass, f *ast.File, spec *ast.ImportSpec, r rule, ignored func() bool) {
+	if spec.Name != nil && (spec.Name.Name == "_" || spec.Name.Name == ".") {
+		return
+	}
+	pkgName := importedPkgName(pass, spec)
+	if pkgName == nil {
+		return
+	}
+	name := pkgName.Name()
+	defaultName := pkgName.Imported().Name()
+
+	var msg, rename string
+	switch {
+	case r.alias != "":
+		if name == r.alias {
+			return
+		}
+		msg = fmt.Sprintf("package %q should be imported with alias %q", importPath(spec), r.alias)
+		rename = r.alias
+	case spec.Name == nil:
+		return
+	case r.noAlias:
+		msg = fmt.Sprintf("package %q shouldn't be imported with an alias", importPath(spec))
+		rename = defaultName
+	default:
+		forbidden := false
+		for _, alias := range r.forbiddenAliases {
+			forbidden = forbidden || alias == name
+		}
+		// Forbidding the package name requires another alias, which is
+		// unknown.
+		if !forbidden || name == defaultName {
+			return
+		}
+		msg = fmt.Sprintf("alias %q of package %q shouldn't be used", name, importPath(spec))
+		rename = defaultName
+	}
+	if ignored() {
+		return
+	}
+	if r.sugg != "" {
+		msg += fmt.Sprintf(", suggested: %q", r.sugg)
+	}
+
+	d := analysis.Diagnostic{Pos: spec.Path.Pos(), Message: msg}
+	if fix, ok := aliasFix(pass, f, spec, pkgName, rename); ok {
+		d.SuggestedFixes = []analysis.SuggestedFix{fix}
+	}
+	pass.Report(d)
+}
+
+// aliasFix returns the suggested fix renaming the import spec and all of its
+// qualified usages in f. If the package is imported with the new name
+// already, the spec is removed instead. No fix is returned if the new name
+// conflicts with another declaration.
+func aliasFix(pass *analysis.Pass, f *ast.File, spec *ast.ImportSpec, pkgName *types.PkgName, rename string) (analysis.SuggestedFix, bool) {
+	// existing is the import of the package with the new name, if any.
+	var existing *types.PkgName
+	for _, other := range f.Imports {
+		otherName := importedPkgName(pass, other)
+		if other == spec || otherName == nil || otherName.Name() != rename {
+			continue
+		}
+		if otherName.Imported() != pkgName.Imported() {
+			return analysis.SuggestedFix{}, false
+		}
+		existing = otherName
+	}
+	if existing == nil && pass.Pkg.Scope().Lookup(rename) != nil {
+		return analysis.SuggestedFix{}, false
+	}
+
+	var edits []analysis.TextEdit
+	usages := pkgNameUsages(pass, f, pkgName)
+	for _, pos := range usages {
+		// The new name must refer to the package at all usages.
+		scope := pass.Pkg.Scope().Innermost(pos)
+		if scope == nil {
+			return analysis.SuggestedFix{}, false
+		}
+		if _, obj := scope.LookupParent(rename, pos); obj != nil && obj != existing && obj != pkgName {
+			return analysis.SuggestedFix{}, false
+		}
+		if rename == pkgName.Name() {
+			continue
+		}
+		edits = append(edits, analysis.TextEdit{Pos: pos, End: pos + token.Pos(len(pkgName.Name())), NewText: []byte(rename)})
+	}
+
+	if existing != nil {
+		pos, end := spec.Pos(), spec.End()
+		if decl := importDecl(f, spec); decl != nil && !decl.Lparen.IsValid() {
+			pos, end = decl.Pos(), decl.End()
+		}
+		edits = append(edits, analysis.TextEdit{Pos: pos, End: end})
+		return analysis.SuggestedFix{
+			Message:   fmt.Sprintf("Use the existing import %s", rename),
+			TextEdits: edits,
+		}, true
+	}
+
+	switch {
+	case spec.Name == nil:
+		edits = append(edits, analysis.TextEdit{Pos: spec.Path.Pos(), End: spec.Path.Pos(), NewText: []byte(rename + " ")})
+	case rename == pkgName.Imported().Name():
+		edits = append(edits, analysis.TextEdit{Pos: spec.Name.Pos(), End: spec.Path.Pos()})
+	default:
+		edits = append(edits, analysis.TextEdit{Pos: spec.Name.Pos(), End: spec.Name.End(), NewText: []byte(rename)})
+	}
+	return analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Rename import to %s", rename),
+		TextEdits: edits,
+	}, true
+}
+
+// importDecl returns the import declaration of f containing spec.
+func importDecl(f *ast.File, spec *ast.ImportSpec) *ast.GenDecl {
+	for _, d := range f.Decls {
+		decl, ok := d.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			continue
+		}
+		for _, s := range decl.Specs {
+			if s == spec {
+				return decl
+			}
+		}
+	}
+	return nil
+}
+
+// importedPkgName returns the package name declared by spec, or nil if it's
+// unknown.
+func importedPkgName(pass *analysis.Pass, spec *ast.ImportSpec) *types.PkgName {
+	var obj types.Object
+	if spec.Name != nil {
+		obj = pass.TypesInfo.Defs[spec.Name]
+	} else {
+		obj = pass.TypesInfo.Implicits[spec]
+	}
+	pkgName, _ := obj.(*types.PkgName)
+	return pkgName
+}
+
+// pkgNameUsages returns the positions of all identifiers in f referring to
+// the package name.
+func pkgNameUsages(pass *analysis.Pass, f *ast.File, pkgName *types.PkgName) []token.Pos {
+	var usages []token.Pos
+	ast.Inspect(f, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == pkgName {
+			usages = append(usages, id.NamePos)
+		}
+		return true
+	})
+	return usages
+}
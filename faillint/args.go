@@ -0,0 +1,384 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+type argKind int
+
+const (
+	// argEqual matches an argument that is a constant equal to value.
+	argEqual argKind = iota
+	// argMatch matches an argument that is a string constant matching re.
+	argMatch
+	// argConst matches an argument that is a constant.
+	argConst
+	// argNonConst matches an argument that is not a constant.
+	argNonConst
+)
+
+// anyArg is the index of an argument constraint, which is satisfied by any of
+// the arguments, i.e: *=~/@latest$/.
+const anyArg = -1
+
+// argConstraint restricts a declaration to calls where the argument at the
+// given index satisfies the constraint. It's parsed from the parenthesized
+// part of a declaration, e.g: Getenv(0="AWS_SECRET_ACCESS_KEY"),
+// MkdirAll(1=0777), MustCompile(0=!const) or Getenv(0=~/^AWS_/).
+type argConstraint struct {
+	index int
+	kind  argKind
+	value constant.Value
+	re    *regexp.Regexp
+}
+
+// decl represents a single parsed declaration of a path.
+type decl struct {
+	// name is the declaration name. It's a glob pattern if it contains any
+	// of the * or ? characters.
+	name string
+	// re is set if the declaration name is a regexp, i.e: /^Must/.
+	re   *regexp.Regexp
+	args []argConstraint
+}
+
+// parseDecl parses a declaration in the form of Name or Name(constraints...).
+// The name can be a glob pattern (i.e: Print*) or a regexp enclosed in
+// slashes (i.e: /^Must/).
+func parseDecl(s string) (decl, error) {
+	var (
+		d    decl
+		rest string
+	)
+	if strings.HasPrefix(s, "/") {
+		end := regexpEnd(s)
+		if end == -1 {
+			return decl{}, fmt.Errorf("declaration %q: missing closing slash", s)
+		}
+		re, err := parseRegexp(s[:end+1])
+		if err != nil {
+			return decl{}, fmt.Errorf("declaration %q: %v", s, err)
+		}
+		d.name, d.re = s[:end+1], re
+		rest = s[end+1:]
+	} else {
+		lparen := strings.IndexByte(s, '(')
+		if lparen == -1 {
+			lparen = len(s)
+		}
+		d.name, rest = s[:lparen], s[lparen:]
+		if _, err := filepath.Match(d.name, ""); err != nil {
+			return decl{}, fmt.Errorf("declaration %q: %v", s, err)
+		}
+	}
+	if rest == "" {
+		return d, nil
+	}
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return decl{}, fmt.Errorf("declaration %q: argument constraints must be enclosed in parentheses", s)
+	}
+
+	for _, c := range splitList(rest[1:len(rest)-1], ',') {
+		ac, err := parseArgConstraint(c)
+		if err != nil {
+			return decl{}, fmt.Errorf("declaration %q: %v", s, err)
+		}
+		d.args = append(d.args, ac)
+	}
+	return d, nil
+}
+
+// regexpEnd returns the index of the slash closing the regexp at the start of
+// s, or -1 if there is none.
+func regexpEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '/':
+			return i
+		}
+	}
+	return -1
+}
+
+// matchName returns true if the declaration with the given name is matched
+// by d.
+func (d decl) matchName(name string) bool {
+	if d.re != nil {
+		return d.re.MatchString(name)
+	}
+	ok, _ := filepath.Match(d.name, name)
+	return ok
+}
+
+// parseArgConstraint parses a single argument constraint in the form of
+// index=value, where value is either a Go literal, `const`, `!const` or a
+// regexp in the form of ~/regexp/. The index * matches any argument.
+func parseArgConstraint(s string) (argConstraint, error) {
+	eq := strings.IndexByte(s, '=')
+	if eq == -1 {
+		return argConstraint{}, fmt.Errorf("argument constraint %q must be in the form of index=value", s)
+	}
+
+	index, err := strconv.Atoi(s[:eq])
+	if s[:eq] == "*" {
+		index, err = anyArg, nil
+	} else if err != nil || index < 0 {
+		return argConstraint{}, fmt.Errorf("argument constraint %q: invalid argument index %q", s, s[:eq])
+	}
+
+	ac := argConstraint{index: index}
+	value := s[eq+1:]
+	switch {
+	case value == "const":
+		ac.kind = argConst
+	case value == "!const":
+		ac.kind = argNonConst
+	case strings.HasPrefix(value, "~"):
+		re, err := parseRegexp(value[1:])
+		if err != nil {
+			return argConstraint{}, fmt.Errorf("argument constraint %q: %v", s, err)
+		}
+		ac.kind = argMatch
+		ac.re = re
+	default:
+		v, err := parseConstant(value)
+		if err != nil {
+			return argConstraint{}, fmt.Errorf("argument constraint %q: %v", s, err)
+		}
+		ac.kind = argEqual
+		ac.value = v
+	}
+	return ac, nil
+}
+
+// parseRegexp compiles a regexp in the form of /regexp/.
+func parseRegexp(s string) (*regexp.Regexp, error) {
+	if len(s) < 2 || s[0] != '/' || s[len(s)-1] != '/' {
+		return nil, fmt.Errorf("regexp %q must be enclosed in slashes", s)
+	}
+	return regexp.Compile(s[1 : len(s)-1])
+}
+
+// parseConstant parses a Go literal, such as "foo", 0777, 1.5, 'x', true or
+// false into a constant value.
+func parseConstant(s string) (constant.Value, error) {
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constant %q", s)
+	}
+
+	neg := false
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.SUB {
+		neg = true
+		expr = u.X
+	}
+
+	var v constant.Value
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v = constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			v = constant.MakeBool(true)
+		case "false":
+			v = constant.MakeBool(false)
+		}
+	}
+	if v == nil || v.Kind() == constant.Unknown || (neg && !isNumeric(v)) {
+		return nil, fmt.Errorf("invalid constant %q", s)
+	}
+	if neg {
+		v = constant.UnaryOp(token.SUB, v, 0)
+	}
+	return v, nil
+}
+
+func isNumeric(v constant.Value) bool {
+	switch v.Kind() {
+	case constant.Int, constant.Float, constant.Complex:
+		return true
+	}
+	return false
+}
+
+// match returns true if the argument of call satisfies the constraint.
+func (ac argConstraint) match(pass *analysis.Pass, call *ast.CallExpr) bool {
+	value := func(arg ast.Expr) constant.Value {
+		if tv, ok := pass.TypesInfo.Types[arg]; ok {
+			return tv.Value
+		}
+		return nil
+	}
+	if ac.index == anyArg {
+		for _, arg := range call.Args {
+			if ac.matchValue(value(arg)) {
+				return true
+			}
+		}
+		return false
+	}
+	if ac.index >= len(call.Args) {
+		return false
+	}
+	return ac.matchValue(value(call.Args[ac.index]))
+}
+
+// matchArgs returns true if the argument of a directive satisfies the
+// constraint. The arguments are string constants, which are parsed as Go
+// literals if the constraint is not a string, so 0777 matches 511 as well.
+func (ac argConstraint) matchArgs(args []string) bool {
+	matchArg := func(arg string) bool {
+		if ac.kind == argEqual && ac.value.Kind() != constant.String {
+			v, err := parseConstant(arg)
+			return err == nil && ac.matchValue(v)
+		}
+		return ac.matchValue(constant.MakeString(arg))
+	}
+	if ac.index == anyArg {
+		return slices.ContainsFunc(args, matchArg)
+	}
+	return ac.index < len(args) && matchArg(args[ac.index])
+}
+
+// matchValue returns true if the argument value v, which is nil for
+// non-constant arguments, satisfies the constraint.
+func (ac argConstraint) matchValue(v constant.Value) bool {
+	switch ac.kind {
+	case argConst:
+		return v != nil
+	case argNonConst:
+		return v == nil
+	case argMatch:
+		return v != nil && v.Kind() == constant.String && ac.re.MatchString(constant.StringVal(v))
+	case argEqual:
+		if v == nil {
+			return false
+		}
+		if v.Kind() != ac.value.Kind() && !(isNumeric(v) && isNumeric(ac.value)) {
+			return false
+		}
+		return constant.Compare(v, token.EQL, ac.value)
+	}
+	return false
+}
+
+// matchCall returns true if the usage at pos is a call satisfying all argument
+// constraints of d. A declaration without any constraints matches all usages.
+func (d decl) matchCall(pass *analysis.Pass, file *ast.File, pos token.Pos) bool {
+	if len(d.args) == 0 {
+		return true
+	}
+	call := callAt(file, pos)
+	if call == nil {
+		return false
+	}
+	for _, ac := range d.args {
+		if !ac.match(pass, call) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchDirective returns true if the directive with the given name and
+// arguments is matched by d.
+func (d decl) matchDirective(name string, args []string) bool {
+	if !d.matchName(name) {
+		return false
+	}
+	for _, ac := range d.args {
+		if !ac.matchArgs(args) {
+			return false
+		}
+	}
+	return true
+}
+
+// callAt returns the call expression whose function is the identifier at pos,
+// or nil if the identifier at pos is not called.
+func callAt(file *ast.File, pos token.Pos) *ast.CallExpr {
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	if len(path) == 0 {
+		return nil
+	}
+	var fun ast.Node = path[0]
+	for _, n := range path[1:] {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if n.Sel != fun {
+				return nil
+			}
+		case *ast.IndexExpr:
+			// Generic instantiation, i.e: foo.Bar[int](x)
+			if n.X != fun {
+				return nil
+			}
+		case *ast.IndexListExpr:
+			if n.X != fun {
+				return nil
+			}
+		case *ast.ParenExpr:
+			// Parenthesized function, i.e: (foo.Bar)(x)
+		case *ast.CallExpr:
+			if n.Fun != fun {
+				return nil
+			}
+			return n
+		default:
+			return nil
+		}
+		fun = n
+	}
+	return nil
+}
+
+// splitList splits s by sep, ignoring separators enclosed in parentheses,
+// braces, quotes or regexps. A regexp is enclosed in slashes and starts either
+// at the beginning of an element or after one of the =~!({ characters, so
+// slashes of import paths are not treated as such.
+func splitList(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '/' && (i == start || strings.IndexByte("=~!({", s[i-1]) != -1):
+			quote = c
+		case c == '(' || c == '{':
+			depth++
+		case c == ')' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
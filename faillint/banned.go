@@ -0,0 +1,106 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	// bannedKey is the directive banning the usage of a declaration in other
+	// packages, i.e: //faillint:banned use NewClientV2 instead
+	bannedKey = "//faillint:banned"
+
+	// testOnlyKey is the directive banning the usage of a declaration in other
+	// packages outside of test files.
+	testOnlyKey = "//faillint:testonly"
+)
+
+// bannedFact is exported for exported declarations, whose doc comment has a
+// banned or testonly directive.
+type bannedFact struct {
+	// Msg is the optional message of the banned directive.
+	Msg string
+
+	// TestOnly is true if the declaration can be used in test files.
+	TestOnly bool
+}
+
+func (*bannedFact) AFact() {}
+
+func (f *bannedFact) String() string {
+	if f.TestOnly {
+		return "testonly"
+	}
+	return strings.TrimSpace("banned: " + f.Msg)
+}
+
+// bannedDirective returns the fact of the banned or testonly directive of
+// doc, if any.
+func bannedDirective(doc *ast.CommentGroup) (*bannedFact, bool) {
+	if doc == nil {
+		return nil, false
+	}
+	for _, c := range doc.List {
+		if c.Text == testOnlyKey {
+			return &bannedFact{TestOnly: true}, true
+		}
+		msg, ok := strings.CutPrefix(c.Text, bannedKey)
+		if ok && (msg == "" || msg[0] == ' ' || msg[0] == '\t') {
+			return &bannedFact{Msg: strings.TrimSpace(msg)}, true
+		}
+	}
+	return nil, false
+}
+
+// exportBanned exports a bannedFact for every exported declaration of the
+// package with a banned or testonly directive.
+func exportBanned(pass *analysis.Pass) {
+	inspectDocs(pass, func(id *ast.Ident, docs ...*ast.CommentGroup) {
+		obj := pass.TypesInfo.Defs[id]
+		if obj == nil || !obj.Exported() {
+			return
+		}
+		for _, doc := range docs {
+			if fact, ok := bannedDirective(doc); ok {
+				pass.ExportObjectFact(obj, fact)
+				return
+			}
+		}
+	})
+}
+
+// reportBanned reports the usages of banned declarations of other packages in
+// file. Declarations banned with the testonly directive are allowed in test
+// files.
+func reportBanned(pass *analysis.Pass, ignores *ignoreDirectives, file *ast.File) {
+	isTestFile := strings.HasSuffix(pass.Fset.File(file.Package).Name(), "_test.go")
+	ast.Inspect(file, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj := origin(pass.TypesInfo.Uses[id])
+		if obj == nil || obj.Pkg() == nil || obj.Pkg() == pass.Pkg {
+			return true
+		}
+		var fact bannedFact
+		if !pass.ImportObjectFact(obj, &fact) || (fact.TestOnly && isTestFile) {
+			return true
+		}
+		if usageHasDirective(pass, ignores, n, id.NamePos, ignoreKey, directiveTargets(obj.Pkg().Path(), objName(obj), "")...) {
+			return true
+		}
+		msg := fmt.Sprintf("declaration %q from package %q shouldn't be used", objName(obj), obj.Pkg().Path())
+		switch {
+		case fact.TestOnly:
+			msg = fmt.Sprintf("declaration %q from package %q should only be used in tests", objName(obj), obj.Pkg().Path())
+		case fact.Msg != "":
+			msg += fmt.Sprintf(", suggested: %q", fact.Msg)
+		}
+		pass.Reportf(id.NamePos, msg)
+		return true
+	})
+}
@@ -0,0 +1,83 @@
+package faillint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// constructs contains the Go language constructs that can be disallowed with
+// a path prefixed with @, mapped to their description.
+var constructs = map[string]string{
+	"go":         "go statement",
+	"goto":       "goto statement",
+	"init":       "init function",
+	"recover":    "recover call",
+	"global-var": "package level variable",
+	"select":     "select statement",
+}
+
+// construct represents a single usage of a Go language construct.
+type construct struct {
+	node ast.Node
+	pos  token.Pos
+}
+
+// constructUsages returns all usages of the given construct in f.
+func constructUsages(pass *analysis.Pass, f *ast.File, kind string) []construct {
+	var usages []construct
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			if kind == "go" {
+				usages = append(usages, construct{n, n.Go})
+			}
+		case *ast.BranchStmt:
+			if kind == "goto" && n.Tok == token.GOTO {
+				usages = append(usages, construct{n, n.TokPos})
+			}
+		case *ast.SelectStmt:
+			if kind == "select" {
+				usages = append(usages, construct{n, n.Select})
+			}
+		case *ast.FuncDecl:
+			if kind == "init" && n.Recv == nil && n.Name.Name == "init" {
+				usages = append(usages, construct{n, n.Name.NamePos})
+			}
+		case *ast.CallExpr:
+			if kind != "recover" {
+				break
+			}
+			id, ok := ast.Unparen(n.Fun).(*ast.Ident)
+			if !ok {
+				break
+			}
+			if b, ok := pass.TypesInfo.Uses[id].(*types.Builtin); ok && b.Name() == "recover" {
+				usages = append(usages, construct{n, id.NamePos})
+			}
+		}
+		return true
+	})
+
+	if kind == "global-var" {
+		for _, d := range f.Decls {
+			gen, ok := d.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					// Blank variables are used for compile time assertions
+					// and can't be mutated.
+					if name.Name == "_" {
+						continue
+					}
+					usages = append(usages, construct{spec, name.NamePos})
+				}
+			}
+		}
+	}
+	return usages
+}
@@ -0,0 +1,323 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/analysis"
+)
+
+// deprecatedFact is exported for objects and packages whose doc comment
+// contains a "Deprecated:" paragraph, and for packages of deprecated modules.
+type deprecatedFact struct {
+	// Msg is the text of the "Deprecated:" paragraph.
+	Msg string
+
+	// Module is set to the module path if the module of the package is
+	// deprecated, instead of the package itself.
+	Module string
+}
+
+func (*deprecatedFact) AFact() {}
+
+func (f *deprecatedFact) String() string { return "deprecated: " + f.Msg }
+
+// deprecation returns the text of the "Deprecated:" paragraph of doc,
+// joined into a single line.
+func deprecation(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, para := range strings.Split(doc.Text(), "\n\n") {
+		if text, ok := strings.CutPrefix(para, "Deprecated:"); ok {
+			return strings.Join(strings.Fields(text), " "), true
+		}
+	}
+	return "", false
+}
+
+// exportDeprecations exports a deprecatedFact for the package if it's
+// documented as deprecated or belongs to a deprecated module, and for every
+// declaration of the package that is documented as deprecated. This includes
+// methods, struct fields and interface methods.
+func exportDeprecations(pass *analysis.Pass) {
+	if fact := packageDeprecation(pass); fact != nil {
+		pass.ExportPackageFact(fact)
+	}
+
+	inspectDocs(pass, func(id *ast.Ident, docs ...*ast.CommentGroup) {
+		obj := pass.TypesInfo.Defs[id]
+		if obj == nil {
+			return
+		}
+		for _, doc := range docs {
+			if msg, ok := deprecation(doc); ok {
+				pass.ExportObjectFact(obj, &deprecatedFact{Msg: msg})
+				return
+			}
+		}
+	})
+}
+
+// inspectDocs calls fn for the name of every declaration of the package with
+// its doc comments, ordered by precedence. This includes methods, struct
+// fields and interface methods.
+func inspectDocs(pass *analysis.Pass, fn func(id *ast.Ident, docs ...*ast.CommentGroup)) {
+	inspectFields := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				fn(name, field.Doc)
+			}
+		}
+	}
+
+	for _, file := range pass.Files {
+		for _, d := range file.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				fn(d.Name, d.Doc)
+			case *ast.GenDecl:
+				// The doc comment of the declaration applies to all of its specs.
+				for _, spec := range d.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						fn(spec.Name, spec.Doc, d.Doc)
+						switch t := spec.Type.(type) {
+						case *ast.StructType:
+							inspectFields(t.Fields)
+						case *ast.InterfaceType:
+							inspectFields(t.Methods)
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							fn(name, spec.Doc, d.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// packageDeprecation returns the deprecation of the package, or nil if it's
+// not deprecated. A package is deprecated if its package doc has a
+// "Deprecated:" paragraph or its go.mod file has a "Deprecated:" comment on
+// the module directive.
+func packageDeprecation(pass *analysis.Pass) *deprecatedFact {
+	for _, file := range pass.Files {
+		if msg, ok := deprecation(file.Doc); ok {
+			return &deprecatedFact{Msg: msg}
+		}
+	}
+	if mod := findModule(pass); mod != nil && mod.Deprecated != "" {
+		return &deprecatedFact{Msg: mod.Deprecated, Module: mod.Mod.Path}
+	}
+	return nil
+}
+
+// findModule returns the module directive of the go.mod file enclosing the
+// files of the package, or nil if there is none. Packages of dependencies
+// are loaded from the module cache, which contains their go.mod files.
+func findModule(pass *analysis.Pass) *modfile.Module {
+	if len(pass.Files) == 0 {
+		return nil
+	}
+	dir := filepath.Dir(sourceFile(pass, pass.Files[0]))
+	mod := moduleOf(dir, strings.TrimSuffix(pass.Pkg.Path(), "_test"))
+	// The module is known for packages loaded in module mode.
+	if mod == nil || (pass.Module != nil && pass.Module.Path != "" && pass.Module.Path != mod.Mod.Path) {
+		return nil
+	}
+	return mod
+}
+
+// moduleOf returns the module directive of the nearest go.mod file enclosing
+// the package with the given directory and import path, or nil if there is
+// none. The go.mod file must belong to the package: the module path joined
+// with the directory relative to the go.mod file must be the import path.
+// This is not the case for vendored packages and packages in GOPATH or
+// GOROOT, which are enclosed by the go.mod file of another module.
+func moduleOf(dir, pkgPath string) *modfile.Module {
+	for start := dir; ; {
+		gomod := filepath.Join(dir, "go.mod")
+		if data, err := os.ReadFile(gomod); err == nil {
+			f, err := modfile.ParseLax(gomod, data, nil)
+			if err != nil || f.Module == nil {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, start)
+			if err != nil {
+				return nil
+			}
+			rel = filepath.ToSlash(rel)
+			if rel == "vendor" || strings.HasPrefix(rel, "vendor/") || strings.Contains(rel, "/vendor/") {
+				return nil
+			}
+			modPkgPath := f.Module.Mod.Path
+			if rel != "." {
+				modPkgPath += "/" + rel
+			}
+			if modPkgPath != pkgPath {
+				return nil
+			}
+			return f.Module
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// deprecations reports the usages of deprecated packages and declarations.
+type deprecations struct {
+	// allowed contains the rules of the deprecations to not report.
+	allowed []rule
+
+	// modPath is the module path of the analyzed package, if any.
+	modPath string
+}
+
+func newDeprecations(pass *analysis.Pass, allowed []rule) *deprecations {
+	d := &deprecations{allowed: allowed}
+	if mod := findModule(pass); mod != nil {
+		d.modPath = mod.Mod.Path
+	}
+	return d
+}
+
+// report reports the imports of deprecated packages and the usages of
+// deprecated declarations of other packages in file, except the allowed ones.
+func (d *deprecations) report(pass *analysis.Pass, ignores *ignoreDirectives, file *ast.File) {
+	for _, spec := range file.Imports {
+		pkg := importedPackage(pass.Pkg, importPath(spec))
+		if pkg == nil {
+			continue
+		}
+		var fact deprecatedFact
+		if !pass.ImportPackageFact(pkg, &fact) || packageAllowed(pkg.Path(), d.allowed) {
+			continue
+		}
+		// Packages of the same module can use each other.
+		if fact.Module != "" && fact.Module == d.modPath {
+			continue
+		}
+		if usageHasDirective(pass, ignores, spec, spec.Pos(), ignoreKey, directiveTargets(pkg.Path(), "", "")...) {
+			continue
+		}
+		if fact.Module != "" {
+			pass.Reportf(spec.Path.Pos(), "module %q of package %q is deprecated, suggested: %q", fact.Module, pkg.Path(), fact.Msg)
+		} else {
+			pass.Reportf(spec.Path.Pos(), "package %q is deprecated, suggested: %q", pkg.Path(), fact.Msg)
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj := origin(pass.TypesInfo.Uses[id])
+		if obj == nil || obj.Pkg() == nil || obj.Pkg() == pass.Pkg {
+			return true
+		}
+		var fact deprecatedFact
+		if !pass.ImportObjectFact(obj, &fact) {
+			return true
+		}
+		if deprecationAllowed(obj, d.allowed) {
+			return true
+		}
+		if usageHasDirective(pass, ignores, n, id.NamePos, ignoreKey, directiveTargets(obj.Pkg().Path(), objName(obj), "")...) {
+			return true
+		}
+		pass.Reportf(id.NamePos, "declaration %q from package %q is deprecated, suggested: %q", objName(obj), obj.Pkg().Path(), fact.Msg)
+		return true
+	})
+}
+
+// importedPackage returns the package with the given path imported by pkg,
+// or nil if there is none.
+func importedPackage(pkg *types.Package, path string) *types.Package {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == path {
+			return imp
+		}
+	}
+	return nil
+}
+
+// packageAllowed returns true if the package with the given path is matched
+// by any of the rules without declarations.
+func packageAllowed(pkgPath string, allowed []rule) bool {
+	for _, r := range allowed {
+		if len(r.parsedDecls) > 0 {
+			continue
+		}
+		if r.matchesPath(pkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecationAllowed returns true if obj is matched by any of the rules.
+func deprecationAllowed(obj types.Object, allowed []rule) bool {
+	pkgPath := obj.Pkg().Path()
+	for _, r := range allowed {
+		if !r.matchesPath(pkgPath) {
+			continue
+		}
+		if len(r.parsedDecls) == 0 {
+			return true
+		}
+		for _, d := range r.parsedDecls {
+			if d.matchName(obj.Name()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// origin returns the declared object of obj. The methods and fields of
+// instantiated generic types are distinct objects, which have no facts.
+func origin(obj types.Object) types.Object {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Origin()
+	case *types.Var:
+		return obj.Origin()
+	}
+	return obj
+}
+
+// objName returns the name of obj, qualified with the receiver type name for
+// methods.
+func objName(obj types.Object) string {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return obj.Name()
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return obj.Name()
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return fmt.Sprintf("%s.%s", named.Obj().Name(), obj.Name())
+	}
+	return obj.Name()
+}
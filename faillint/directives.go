@@ -0,0 +1,234 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// directiveKinds contains the prefixes of the paths of directive rules, i.e:
+// @go:linkname, @cgo:{LDFLAGS(*=~/^-l/)} or @generate:mockgen.
+var directiveKinds = []string{"go", "cgo", "generate"}
+
+// directive is a compiler directive, i.e: //go:linkname local remote, or a
+// cgo directive of the preamble of an import "C", i.e: #cgo LDFLAGS: -lpng.
+// The command of a //go:generate directive is a directive of its own, whose
+// name is the command and whose arguments are the command arguments.
+type directive struct {
+	// kind is either go, cgo or generate.
+	kind string
+	name string
+	args []string
+	pos  token.Pos
+
+	// cg is the comment group containing the directive.
+	cg *ast.CommentGroup
+}
+
+// String returns the directive as it's written in the source, without its
+// arguments.
+func (d directive) String() string {
+	switch d.kind {
+	case "cgo":
+		return "#cgo " + d.name
+	case "generate":
+		return "//go:generate " + d.name
+	}
+	return "//go:" + d.name
+}
+
+// cgoHeader is the comment, which marks the files generated by cgo.
+const cgoHeader = "// Code generated by cmd/cgo; DO NOT EDIT."
+
+// fileDirectives returns the go directives of all comments of f and the cgo
+// directives of the preambles of its import "C" declarations.
+func fileDirectives(f *ast.File) []directive {
+	var directives []directive
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			text, ok := strings.CutPrefix(c.Text, "//go:")
+			if !ok {
+				continue
+			}
+			fields := splitArgs(text)
+			if len(fields) == 0 {
+				continue
+			}
+			directives = append(directives, directive{
+				kind: "go",
+				name: fields[0],
+				args: fields[1:],
+				pos:  c.Pos(),
+				cg:   cg,
+			})
+			if fields[0] == "generate" && len(fields) > 1 {
+				directives = append(directives, directive{
+					kind: "generate",
+					name: fields[1],
+					args: fields[2:],
+					pos:  c.Pos(),
+					cg:   cg,
+				})
+			}
+		}
+	}
+
+	for _, d := range f.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.ImportSpec)
+			// Files processed by cgo import unsafe instead of C.
+			if importPath(spec) != "C" && !(isCgoFile(f) && importPath(spec) == "unsafe" && spec.Name != nil && spec.Name.Name == "_") {
+				continue
+			}
+			// The preamble is the doc comment of the spec, or of the
+			// declaration if the spec is not in parentheses.
+			for _, cg := range []*ast.CommentGroup{spec.Doc, gen.Doc} {
+				if cg != nil {
+					directives = append(directives, cgoDirectives(cg)...)
+				}
+			}
+		}
+	}
+	return directives
+}
+
+// isCgoFile returns true if f is generated by cgo, either from a source file
+// importing "C" or from scratch.
+func isCgoFile(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if c.Text == cgoHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sourceFile returns the name of the file f is parsed from. The files
+// generated by cgo from a source file are analyzed in place of the source
+// file, which is named by the line directive before their package clause.
+func sourceFile(pass *analysis.Pass, f *ast.File) string {
+	if isCgoFile(f) {
+		return pass.Fset.Position(f.Package).Filename
+	}
+	return pass.Fset.File(f.Package).Name()
+}
+
+// splitArgs splits the arguments of a go directive separated by spaces. Like
+// go generate does, a double quoted argument is unquoted as a Go string and
+// can contain spaces. So can a back quoted one.
+func splitArgs(s string) []string {
+	var args []string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return args
+		}
+
+		end := strings.IndexAny(s, " \t")
+		if end == -1 {
+			end = len(s)
+		}
+		if s[0] == '"' || s[0] == '`' {
+			// Find the end of the quoted argument.
+			for i := 1; i < len(s); i++ {
+				if s[i] == '\\' && s[0] == '"' {
+					i++
+				} else if s[i] == s[0] {
+					end = i + 1
+					break
+				}
+			}
+		}
+
+		arg := s[:end]
+		if unquoted, err := strconv.Unquote(arg); err == nil {
+			arg = unquoted
+		}
+		args = append(args, arg)
+		s = s[end:]
+	}
+}
+
+// cgoDirectives returns the cgo directives of the preamble cg, which are in
+// the form of #cgo [GOOS GOARCH...] NAME: args.
+func cgoDirectives(cg *ast.CommentGroup) []directive {
+	var directives []directive
+	for _, c := range cg.List {
+		offset := 0
+		for _, line := range strings.SplitAfter(c.Text, "\n") {
+			lineOffset := offset
+			offset += len(line)
+
+			text := strings.TrimLeft(line, " \t/*")
+			text, ok := strings.CutPrefix(text, "#cgo")
+			if !ok || text == "" || (text[0] != ' ' && text[0] != '\t') {
+				continue
+			}
+			lhs, rhs, ok := strings.Cut(text, ":")
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(lhs)
+			if len(fields) == 0 {
+				continue
+			}
+			directives = append(directives, directive{
+				kind: "cgo",
+				name: fields[len(fields)-1],
+				args: strings.Fields(strings.TrimSuffix(strings.TrimSpace(rhs), "*/")),
+				pos:  c.Pos() + token.Pos(lineOffset+strings.Index(line, "#cgo")),
+				cg:   cg,
+			})
+		}
+	}
+	return directives
+}
+
+// reportDirectives reports the directives of f matched by the directive rule.
+func reportDirectives(pass *analysis.Pass, ignores *ignoreDirectives, f *ast.File, r rule) {
+	for _, d := range fileDirectives(f) {
+		if d.kind != r.directive {
+			continue
+		}
+		matched := slices.ContainsFunc(r.parsedDecls, func(pd decl) bool {
+			return pd.matchDirective(d.name, d.args)
+		})
+		if !matched || !r.inScope(pass, f, d.pos) || directiveIgnored(pass, ignores, d.cg, directiveTargets(r.imp, d.name, r.id)) {
+			continue
+		}
+		msg := fmt.Sprintf("directive %q shouldn't be used", d.String())
+		if r.sugg != "" {
+			msg += fmt.Sprintf(", suggested: %q", r.sugg)
+		}
+		pass.Reportf(d.pos, msg)
+	}
+}
+
+// directiveIgnored returns true if the comment group of a directive, or the
+// node it's attached to, has an ignore directive applying to any of the
+// targets, or if the comment group is in an ignored range.
+func directiveIgnored(pass *analysis.Pass, ignores *ignoreDirectives, cg *ast.CommentGroup, targets []string) bool {
+	if ignores.hasDirective(pass, cg, ignoreKey, targets...) {
+		return true
+	}
+	for node, cgs := range ignores.cm {
+		if slices.Contains(cgs, cg) && usageHasDirective(pass, ignores, node, node.Pos(), ignoreKey, targets...) {
+			return true
+		}
+	}
+	return ignores.inRange(cg.Pos(), targets)
+}
@@ -7,7 +7,11 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -21,12 +25,25 @@ const (
 	ignoreKey = "ignore"
 	// fileIgnoreKey is used in a faillint directive to ignore a whole file.
 	fileIgnoreKey = "file-ignore"
+	// ignoreStartKey is used in a faillint directive to start a range of
+	// ignored problems.
+	ignoreStartKey = "ignore-start"
+	// ignoreEndKey is used in a faillint directive to end a range of ignored
+	// problems.
+	ignoreEndKey = "ignore-end"
 	// missingReasonTemplate is used when a faillint directive is missing a reason.
 	missingReasonTemplate = "missing reason on faillint:%s directive"
-	// unrecognizedOptionTemplate is used when a faillint directive has an option other than ignore or file-ignore.
+	// unrecognizedOptionTemplate is used when a faillint directive has an option other than ignore, file-ignore,
+	// ignore-start or ignore-end.
 	unrecognizedOptionTemplate = "unrecognized option on faillint directive: %s"
+	// unsupportedTargetsTemplate is used when a faillint directive other than
+	// ignore or ignore-start has targets.
+	unsupportedTargetsTemplate = "targets are not supported on faillint:%s directive"
 
 	unspecifiedUsage = "unspecified"
+
+	// builtinPath is the pseudo import path of the builtin functions.
+	builtinPath = "builtin"
 )
 
 var (
@@ -35,13 +52,20 @@ var (
 	Analyzer = NewAnalyzer()
 
 	// pathsRegexp represents a regexp that is used to parse -paths flag.
-	// It parses flag content in set of 3 subgroups:
+	// It parses flag content in set of subgroups:
 	//
 	// * import: Mandatory part. Go import path in URL format to be unwanted or have unwanted declarations.
+	//   Go language constructs are prefixed with @, i.e: @go.
+	//   Package names are prefixed with name:, i.e: name:util*.
+	//   Compiler, cgo and go generate directives are prefixed with @go:,
+	//   @cgo: and @generate:, i.e: @go:linkname or @generate:mockgen.
 	// * recursive: Optional part. Import paths in the form of foo/bar/... indicates that all recursive sub matchs should be also matched.
 	// * declarations: Optional declarations in `{ }`. If set, using the import is allowed expect give declarations.
+	//   Declarations can be glob patterns, i.e: Print*, or regexps enclosed in slashes, i.e: /^Must/.
+	//   Each declaration can have argument constraints in `( )`, i.e: Getenv(0="AWS_SECRET_ACCESS_KEY").
+	// * options: Optional options in `[ ]`, i.e: [blank,in=.../cmd/...].
 	// * suggestion: Optional suggestion to print when unwanted import or declaration is found.
-	pathsRegexp = regexp.MustCompile(`(?P<import>[\w/.-]+[\w])(/?(?P<recursive>\.\.\.)|)(\.?{(?P<declarations>[\w-,]+)}|)(=(?P<suggestion>[\w/.-]+[\w](\.?{[\w-,]+}|))|)`)
+	pathsRegexp = regexp.MustCompile(`(?P<import>name:[\w*?-]+|@(?:c?go|generate):[\w*?-]*|@?[\w/.$!-]+[\w])(/?(?P<recursive>\.\.\.)|)(\.?{(?P<declarations>(?:[\w-,*?]|/(?:\\.|[^/\\])*/|\((?:"(?:\\.|[^"\\])*"|/(?:\\.|[^/\\])*/|[^)"/])*\))+)}|)(\[(?P<options>(?:"(?:\\.|[^"\\])*"|/(?:\\.|[^/\\])*/|[^\]"])*)\]|)(=(?P<suggestion>[\w/.-]+[\w](\.?{[\w-,]+}|))|)`)
 )
 
 // path represents a single parsed directive parsed with the pathsRegexp regex
@@ -53,17 +77,29 @@ type path struct {
 	// for the given import path as well.
 	recursive bool
 
-	// declarations contains the declarations to fail for the given import path
+	// declarations contains the declarations to fail for the given import path.
+	// A declaration might be followed by argument constraints, i.e: MkdirAll(1=0777).
 	decls []string
 
+	// opts contains the options in `[ ]` restricting where and how the path is matched.
+	opts []string
+
 	// sugg defines the suggestion for a given import path
 	sugg string
 }
 
 type faillint struct {
-	paths       string // -paths flag
-	ignoretests bool   // -ignore-tests flag
-	onlyTests   bool   // -only-tests flag
+	paths           string // -paths flag
+	ignoretests     bool   // -ignore-tests flag
+	onlyTests       bool   // -only-tests flag
+	deprecated      bool   // -deprecated flag
+	deprecatedAllow string // -deprecated-allow flag
+	wrappers        bool   // -wrappers flag
+	visibility      bool   // -visibility flag
+	banned          bool   // -banned flag
+	profiles        string // -profiles flag
+	unused          bool   // -unused-directives flag
+	ignoreEnclosing bool   // -ignore-enclosing flag
 }
 
 // NewAnalyzer create a faillint analyzer.
@@ -79,8 +115,13 @@ func NewAnalyzer() *analysis.Analyzer {
 		Run:              f.run,
 		RunDespiteErrors: true,
 	}
+	// The fact types are set by the flags of the checks using them.
+	updateFacts := func() { a.FactTypes = f.factTypes() }
+	factVar := func(p *bool, name, usage string) {
+		a.Flags.Var(&factFlag{p, updateFacts}, name, usage)
+	}
 
-	a.Flags.StringVar(&f.paths, "paths", "", `import paths or exported declarations (i.e: functions, constant, types or variables) to fail. E.g.:
+	a.Flags.Var(&factString{&f.paths, updateFacts}, "paths", `import paths or exported declarations (i.e: functions, constant, types or variables) to fail. E.g.:
 
 Fail on the usage of errors and fmt.Errorf. Also suggest packages for the failures
   -paths errors=github.com/pkg/errors,fmt.{Errorf}=github.com/pkg/errors.{Errorf}
@@ -88,31 +129,181 @@ Fail on the usage of errors and fmt.Errorf. Also suggest packages for the failur
 Fail on the usage of fmt.Println, fmt.Print and fmt.Printf
   -paths fmt.{Println,Print,Printf}
 
+Fail on the usage of fmt.Sprintf, which can be ignored with //lint:ignore faillint:no-sprintf reason
+  -paths fmt.{Sprintf}[id=no-sprintf]
+
+Fail on the usage of all Print functions of fmt and the os.Exit function
+  -paths fmt.{Print*,Fprint*},os.{/^Exit$/}
+
+Fail on the usage of time.Sleep in test functions and log.Fatal outside of the main function
+  -paths time.{Sleep}[scope=test],log.{Fatal}[scope=!main]
+
+Fail on go statements outside of cmd packages and on package level variables
+  -paths @go[in=!.../cmd/...],@global-var
+
+Fail on linkname directives and cgo directives linking libraries
+  -paths @go:linkname,@cgo:{LDFLAGS(*=~/^-l/)}
+
+Fail on go generate directives running tools at their latest version
+  -paths @generate:{go(*=~/@latest$/)}
+
+Fail on the usage of the println and print builtin functions
+  -paths builtin.{println,print}
+
+Fail on imports of another service by any of the services/<name> packages
+  -paths services/!$svc/...[in=services/$svc/...]
+
+Fail on imports depending on net/http, directly or through any of their dependencies
+  -paths net/http[transitive]
+
+Fail if k8s.io/api/core/v1 is not imported as corev1 and if errors is imported with an alias
+  -paths k8s.io/api/core/v1[alias=corev1],errors[noalias]
+
+Fail if a main package doesn't blank import go.uber.org/automaxprocs
+  -paths go.uber.org/automaxprocs[required,blank,pkgname=main]
+
+Fail on imports of packages named util, common or starting with helper, regardless of their import path
+  -paths name:util,name:common,name:helper*
+
 Fail on the usage of prometheus.DefaultGatherer and prometheus.MustRegister
   -paths github.com/prometheus/client_golang/prometheus.{DefaultGatherer,MustRegister}
 
 Fail on the usage of errors, golang.org/x/net and all sub packages under golang.org/x/net
-  -paths errors,golang.org/x/net/...`)
+  -paths errors,golang.org/x/net/...
+
+Fail on calls of os.Getenv with a constant "AWS_SECRET_ACCESS_KEY" argument and regexp.MustCompile with a non-constant argument
+  -paths os.{Getenv(0="AWS_SECRET_ACCESS_KEY")},regexp.{MustCompile(0=!const)}`)
 
 	a.Flags.BoolVar(&f.ignoretests, "ignore-tests", false, "ignore all _test.go files")
 	a.Flags.BoolVar(&f.onlyTests, "only-tests", false, "include only _test.go files")
+	factVar(&f.deprecated, "deprecated", "fail on the usage of declarations documented with a \"Deprecated:\" paragraph")
+	a.Flags.StringVar(&f.deprecatedAllow, "deprecated-allow", "", "import paths or declarations in the -paths format, whose deprecated declarations are allowed with the -deprecated flag")
+	factVar(&f.wrappers, "wrappers", "fail on calls of functions of other packages, which directly or transitively call banned declarations")
+	factVar(&f.visibility, "visibility", "fail on imports of packages, which declare a visibility with a //faillint:visibility directive that doesn't include the importing package")
+	factVar(&f.banned, "banned", "fail on the usage of declarations marked with a //faillint:banned directive, or a //faillint:testonly directive outside of test files")
+	a.Flags.BoolVar(&f.unused, "unused-directives", true, "fail on //lint:ignore faillint, //lint:ignore-start faillint and //lint:file-ignore faillint directives, which don't suppress any problem")
+	a.Flags.BoolVar(&f.ignoreEnclosing, "ignore-enclosing", false, "apply //lint:ignore faillint directives to all problems within the nodes they're attached to, such as whole function bodies, instead of only the next statement or line")
+	a.Flags.Var(&factString{&f.profiles, updateFacts}, "profiles", "rule profiles in the form of name:paths;name:paths, whose paths only apply to functions or files annotated with a //faillint:profile name directive")
 	return a
 }
 
+// factTypes returns the types of the facts used by the enabled checks. Facts
+// make the driver load and analyze all dependencies of a package from source,
+// so they're only used if needed.
+func (f *faillint) factTypes() []analysis.Fact {
+	var facts []analysis.Fact
+	if f.deprecated {
+		facts = append(facts, new(deprecatedFact))
+	}
+	if f.wrappers {
+		facts = append(facts, new(wrapperFact))
+	}
+	if f.visibility {
+		facts = append(facts, new(visibilityFact))
+	}
+	if f.banned {
+		facts = append(facts, new(bannedFact))
+	}
+	if f.hasTransitiveRules() {
+		facts = append(facts, new(importsFact))
+	}
+	return facts
+}
+
+// hasTransitiveRules returns true if any of the paths or profiles is
+// transitive. Invalid paths are reported by run.
+func (f *faillint) hasTransitiveRules() bool {
+	profilePaths, _ := parseProfiles(f.profiles)
+	rules, _ := compileRules(append(parsePaths(f.paths), profilePaths...))
+	return slices.ContainsFunc(rules, isTransitive)
+}
+
+// factString is a string flag of paths, which may enable checks using facts.
+// The update function is called whenever the flag is set.
+type factString struct {
+	value  *string
+	update func()
+}
+
+func (f *factString) Set(s string) error {
+	*f.value = s
+	f.update()
+	return nil
+}
+
+func (f *factString) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+// factFlag is a boolean flag enabling a check, which uses facts. The update
+// function is called whenever the flag is set.
+type factFlag struct {
+	value  *bool
+	update func()
+}
+
+func (f *factFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*f.value = v
+	f.update()
+	return nil
+}
+
+func (f *factFlag) String() string {
+	if f.value == nil {
+		return "false"
+	}
+	return strconv.FormatBool(*f.value)
+}
+
+func (f *factFlag) IsBoolFlag() bool { return true }
+
+// trimAllWhitespaces removes all whitespaces from str, except the ones
+// enclosed in double quotes.
 func trimAllWhitespaces(str string) string {
 	var b strings.Builder
 	b.Grow(len(str))
+	quoted, escaped := false, false
 	for _, ch := range str {
-		if !unicode.IsSpace(ch) {
-			b.WriteRune(ch)
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && ch == '\\':
+			escaped = true
+		case ch == '"':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(ch):
+			continue
 		}
+		b.WriteRune(ch)
 	}
 	return b.String()
 }
 
 // run is the runner for an analysis pass.
 func (f *faillint) run(pass *analysis.Pass) (interface{}, error) {
-	if f.paths == "" {
+	if f.deprecated {
+		// Facts are exported for all packages, including dependencies, so
+		// their deprecated declarations are known to importing packages.
+		exportDeprecations(pass)
+	}
+
+	if f.visibility {
+		exportVisibility(pass)
+	}
+	if f.banned {
+		exportBanned(pass)
+	}
+
+	localRules := forbidRules(pass)
+
+	if f.paths == "" && f.profiles == "" && !f.deprecated && !f.visibility && !f.banned && len(localRules) == 0 {
 		return nil, nil
 	}
 
@@ -120,8 +311,65 @@ func (f *faillint) run(pass *analysis.Pass) (interface{}, error) {
 		return nil, errors.New("--ignore-tests and --only-tests flags cannot be used together")
 	}
 
+	profilePaths, err := parseProfiles(f.profiles)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := compileRules(append(parsePaths(f.paths), profilePaths...))
+	if err != nil {
+		return nil, err
+	}
+
+	deprecatedAllowed, err := compileRules(parsePaths(f.deprecatedAllow))
+	if err != nil {
+		return nil, err
+	}
+	// The module of the package is only looked up if deprecations are
+	// reported.
+	var deprecations *deprecations
+	if f.deprecated {
+		deprecations = newDeprecations(pass, deprecatedAllowed)
+	}
+
+	// used contains the lint directives of the package, which suppressed a
+	// problem.
+	used := make(map[*ast.Comment]bool)
+
+	if f.wrappers {
+		// Like deprecations, wrappers are exported for dependencies as well.
+		exportWrappers(pass, rules, f.ignoreEnclosing, used)
+	}
+	if slices.ContainsFunc(rules, isTransitive) {
+		// The imports are exported for dependencies, whose export data
+		// doesn't contain all of their imports.
+		exportImports(pass)
+	}
+	// Rules of forbid directives apply to their own package only, so they
+	// don't make wrappers for the importing packages.
+	rules = append(rules, localRules...)
+
+	var imports map[string][]string
+	if slices.ContainsFunc(rules, isTransitive) {
+		imports = importGraph(pass)
+	}
+
+	// reportFile is the first analyzed file, on which the problems concerning
+	// the whole package are reported.
+	var (
+		reportFile *ast.File
+		analyzed   []*ast.File
+		ignored    = make(map[*token.File]*ast.Comment)
+	)
+	// Unused directives are reported regardless of the file-ignore
+	// directives.
+	unusedPass := pass
+	if f.unused {
+		// Ignored files are analyzed as well, but their problems are
+		// suppressed, to find the unused file-ignore directives.
+		pass = suppressIgnoredFiles(pass, ignored, used)
+	}
 	for _, file := range pass.Files {
-		filename := pass.Fset.File(file.Package).Name()
+		filename := sourceFile(pass, file)
 		isGenerated, err := generated.ParseFile(filename)
 		if err != nil {
 			return nil, err
@@ -131,7 +379,7 @@ func (f *faillint) run(pass *analysis.Pass) (interface{}, error) {
 			continue
 		}
 
-		isTestFile := strings.Contains(pass.Fset.File(file.Package).Name(), "_test.go")
+		isTestFile := strings.Contains(filename, "_test.go")
 
 		if f.ignoretests && isTestFile {
 			continue
@@ -141,29 +389,113 @@ func (f *faillint) run(pass *analysis.Pass) (interface{}, error) {
 			continue
 		}
 
-		if anyHasDirective(pass, file.Comments, fileIgnoreKey) {
-			continue
+		analyzed = append(analyzed, file)
+		if c := fileIgnoreDirective(pass, file.Comments); c != nil {
+			if !f.unused {
+				continue
+			}
+			ignored[pass.Fset.File(file.Package)] = c
+		} else if reportFile == nil {
+			reportFile = file
+		}
+		ignores := newIgnoreDirectives(pass, file, f.ignoreEnclosing, used)
+		ignores.reportProblems(pass)
+		if f.deprecated {
+			deprecations.report(pass, ignores, file)
 		}
-		commentMap := ast.NewCommentMap(pass.Fset, file, file.Comments)
-		for _, path := range parsePaths(f.paths) {
-			specs := importSpec(file, path.imp, path.recursive)
+		if f.wrappers {
+			reportWrappers(pass, ignores, file, rules)
+		}
+		if f.visibility {
+			reportVisibility(pass, ignores, file)
+		}
+		if f.banned {
+			reportBanned(pass, ignores, file)
+		}
+
+		for _, path := range rules {
+			if !path.appliesTo(pass.Pkg) || path.required {
+				continue
+			}
+
+			if path.construct != "" {
+				msg := fmt.Sprintf("%s shouldn't be used", constructs[path.construct])
+				if path.sugg != "" {
+					msg += fmt.Sprintf(", suggested: %q", path.sugg)
+				}
+				for _, c := range constructUsages(pass, file, path.construct) {
+					if !path.inScope(pass, file, c.pos) || usageHasDirective(pass, ignores, c.node, c.pos, ignoreKey, directiveTargets(path.imp, "", path.id)...) {
+						continue
+					}
+					pass.Reportf(c.pos, msg)
+				}
+				continue
+			}
+
+			if path.imp == builtinPath {
+				ignored := func(name string, pos token.Pos) bool {
+					return usageHasDirective(pass, ignores, nil, pos, ignoreKey, directiveTargets(builtinPath, name, path.id)...)
+				}
+				path.reportUsages(pass, file, builtinUsages(pass, file), ignored, func(name string) string {
+					return fmt.Sprintf("builtin function %q shouldn't be used", name)
+				})
+				continue
+			}
+
+			if path.directive != "" {
+				reportDirectives(pass, ignores, file, path)
+				continue
+			}
+
+			if path.importedName != "" {
+				reportImportedNames(pass, ignores, file, path)
+				continue
+			}
+
+			if path.transitive {
+				reportTransitiveImports(pass, ignores, file, path, imports)
+			}
+
+			specs := path.importSpecs(pass.Pkg, file)
 			if len(specs) == 0 {
 				continue
 			}
 
 			for _, spec := range specs {
-				if usageHasDirective(pass, commentMap, spec, spec.Pos(), ignoreKey) {
+				if !path.matchesSpec(spec) {
 					continue
 				}
 
-				usages := importUsages(pass, commentMap, file, spec)
+				// The directives are checked only before reporting, so
+				// the directives not suppressing anything are known.
+				specIgnored := func(name string) bool {
+					return usageHasDirective(pass, ignores, spec, spec.Pos(), ignoreKey, directiveTargets(importPath(spec), name, path.id)...)
+				}
+
+				if path.isAliasRule() {
+					reportAlias(pass, file, spec, path, func() bool { return specIgnored("") })
+					continue
+				}
+
+				usages := importUsages(pass, file, spec)
 				if len(usages) == 0 {
 					continue
 				}
 
-				if _, ok := usages[unspecifiedUsage]; ok || len(path.decls) == 0 {
+				if _, ok := usages[unspecifiedUsage]; ok || len(path.parsedDecls) == 0 {
+					if len(path.scopes) > 0 {
+						// Scoped paths only match usages, which are unknown for this import.
+						continue
+					}
+					if specIgnored("") {
+						continue
+					}
+
 					// File using unwanted import. Report.
 					msg := fmt.Sprintf("package %q shouldn't be imported", importPath(spec))
+					if path.blank {
+						msg = fmt.Sprintf("package %q shouldn't be imported with a blank identifier", importPath(spec))
+					}
 					if path.sugg != "" {
 						msg += fmt.Sprintf(", suggested: %q", path.sugg)
 					}
@@ -172,28 +504,84 @@ func (f *faillint) run(pass *analysis.Pass) (interface{}, error) {
 				}
 
 				// Not all usages are forbidden. Report only unwanted declarations.
-				for _, declaration := range path.decls {
-					positions, ok := usages[declaration]
-					if !ok {
-						continue
-					}
-					msg := fmt.Sprintf("declaration %q from package %q shouldn't be used", declaration, importPath(spec))
-					if path.sugg != "" {
-						msg += fmt.Sprintf(", suggested: %q", path.sugg)
-					}
-					for _, pos := range positions {
-						pass.Reportf(pos, msg)
-					}
+				ignored := func(name string, pos token.Pos) bool {
+					return specIgnored(name) || usageHasDirective(pass, ignores, nil, pos, ignoreKey, directiveTargets(importPath(spec), name, path.id)...)
 				}
+				path.reportUsages(pass, file, usages, ignored, func(name string) string {
+					return fmt.Sprintf("declaration %q from package %q shouldn't be used", name, importPath(spec))
+				})
 			}
 		}
 	}
 
+	if reportFile != nil {
+		reportRequired(pass, newIgnoreDirectives(pass, reportFile, f.ignoreEnclosing, used), reportFile, rules)
+	}
+	if f.unused {
+		reportUnusedDirectives(unusedPass, analyzed, used)
+	}
+
 	return nil, nil
 }
 
+// reportRequired reports the required paths of rules, which are not imported
+// by any file of the package, on the package clause of f.
+func reportRequired(pass *analysis.Pass, ignores *ignoreDirectives, f *ast.File, rules []rule) {
+	// External test packages are not expected to import the required paths.
+	if strings.HasSuffix(pass.Pkg.Name(), "_test") {
+		return
+	}
+	for _, r := range rules {
+		if !r.required || !r.appliesTo(pass.Pkg) {
+			continue
+		}
+		imported := false
+		for _, file := range pass.Files {
+			for _, spec := range importSpec(file, r.imp, r.recursive) {
+				imported = imported || r.matchesSpec(spec)
+			}
+		}
+		if imported || usageHasDirective(pass, ignores, f, f.Package, ignoreKey, directiveTargets(r.imp, "", r.id)...) {
+			continue
+		}
+		msg := fmt.Sprintf("package %q should import %q", pass.Pkg.Path(), r.imp)
+		if r.blank {
+			msg = fmt.Sprintf("package %q should blank import %q", pass.Pkg.Path(), r.imp)
+		}
+		if r.sugg != "" {
+			msg += fmt.Sprintf(", suggested: %q", r.sugg)
+		}
+		pass.Reportf(f.Package, msg)
+	}
+}
+
+// reportUsages reports the usages of the declarations matched by the rule.
+// The message of a reported usage is returned by msgf for the declaration
+// name.
+func (r *rule) reportUsages(pass *analysis.Pass, file *ast.File, usages map[string][]token.Pos, ignored func(name string, pos token.Pos) bool, msgf func(name string) string) {
+	reported := map[token.Pos]bool{}
+	for _, declaration := range r.parsedDecls {
+		for _, name := range sortedNames(usages) {
+			if !declaration.matchName(name) {
+				continue
+			}
+			msg := msgf(name)
+			if r.sugg != "" {
+				msg += fmt.Sprintf(", suggested: %q", r.sugg)
+			}
+			for _, pos := range usages[name] {
+				if reported[pos] || !declaration.matchCall(pass, file, pos) || !r.inScope(pass, file, pos) || ignored(name, pos) {
+					continue
+				}
+				reported[pos] = true
+				pass.Reportf(pos, msg)
+			}
+		}
+	}
+}
+
 // importUsages reports all exported declaration used for a given import.
-func importUsages(pass *analysis.Pass, commentMap ast.CommentMap, f *ast.File, spec *ast.ImportSpec) map[string][]token.Pos {
+func importUsages(pass *analysis.Pass, f *ast.File, spec *ast.ImportSpec) map[string][]token.Pos {
 	importRef := spec.Name.String()
 	switch importRef {
 	case "<nil>":
@@ -204,7 +592,9 @@ func importUsages(pass *analysis.Pass, commentMap ast.CommentMap, f *ast.File, s
 		if lastSlash != -1 {
 			importRef = importRef[lastSlash+1:]
 		}
-	case "_", ".":
+	case ".":
+		return dotImportUsages(pass, f, spec)
+	case "_":
 		// Not sure if this import is used - on the side of caution, report special "unspecified" usage.
 		return map[string][]token.Pos{unspecifiedUsage: nil}
 	}
@@ -216,9 +606,6 @@ func importUsages(pass *analysis.Pass, commentMap ast.CommentMap, f *ast.File, s
 			return true
 		}
 		if isTopName(sel.X, importRef) {
-			if usageHasDirective(pass, commentMap, n, sel.Sel.NamePos, ignoreKey) {
-				return true
-			}
 			usages[sel.Sel.Name] = append(usages[sel.Sel.Name], sel.Sel.NamePos)
 		}
 		return true
@@ -226,6 +613,142 @@ func importUsages(pass *analysis.Pass, commentMap ast.CommentMap, f *ast.File, s
 	return usages
 }
 
+// dotImportUsages reports all exported declarations used for a given dot
+// import. As dot imported declarations are not qualified, identifiers are
+// resolved to the imported package with the type information.
+func dotImportUsages(pass *analysis.Pass, f *ast.File, spec *ast.ImportSpec) map[string][]token.Pos {
+	impPath := importPath(spec)
+	usages := map[string][]token.Pos{}
+
+	// Qualified identifiers are found by importUsages for other imports of
+	// the package.
+	qualified := map[*ast.Ident]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			qualified[sel.Sel] = true
+		}
+		id, ok := n.(*ast.Ident)
+		if !ok || qualified[id] {
+			return true
+		}
+		obj := pass.TypesInfo.Uses[id]
+		if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != impPath {
+			return true
+		}
+		// Only package level declarations are accessible through a dot
+		// import. This excludes fields and methods.
+		if obj.Parent() != obj.Pkg().Scope() {
+			return true
+		}
+		usages[id.Name] = append(usages[id.Name], id.NamePos)
+		return true
+	})
+	return usages
+}
+
+// sortedNames returns the declaration names of usages in sorted order.
+func sortedNames(usages map[string][]token.Pos) []string {
+	names := make([]string, 0, len(usages))
+	for name := range usages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinUsages reports all builtin functions used in f. Identifiers are
+// resolved with the type information, so local declarations shadowing a
+// builtin function are not reported. Functions of the unsafe package, which
+// are builtins as well, are skipped.
+func builtinUsages(pass *analysis.Pass, f *ast.File) map[string][]token.Pos {
+	usages := map[string][]token.Pos{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if obj, ok := pass.TypesInfo.Uses[id].(*types.Builtin); !ok || obj.Pkg() != nil {
+			return true
+		}
+		usages[id.Name] = append(usages[id.Name], id.NamePos)
+		return true
+	})
+	return usages
+}
+
+// reportImportedNames reports the imports of f, whose imported package has a
+// name matched by the rule.
+func reportImportedNames(pass *analysis.Pass, ignores *ignoreDirectives, f *ast.File, r rule) {
+	for _, spec := range f.Imports {
+		if !r.matchesSpec(spec) {
+			continue
+		}
+		pkg := importedPackage(pass.Pkg, importPath(spec))
+		if pkg == nil {
+			continue
+		}
+		if ok, _ := filepath.Match(r.importedName, pkg.Name()); !ok {
+			continue
+		}
+		if usageHasDirective(pass, ignores, spec, spec.Pos(), ignoreKey, directiveTargets(pkg.Path(), "", r.id)...) {
+			continue
+		}
+		msg := fmt.Sprintf("package %q with name %q shouldn't be imported", pkg.Path(), pkg.Name())
+		if r.sugg != "" {
+			msg += fmt.Sprintf(", suggested: %q", r.sugg)
+		}
+		pass.Reportf(spec.Path.Pos(), msg)
+	}
+}
+
+// reportTransitiveImports reports the imports of f, which aren't matched by
+// the rule themselves, but depend on a package matched by the rule through
+// their transitive dependencies. The imports of the packages are given by
+// their import path.
+func reportTransitiveImports(pass *analysis.Pass, ignores *ignoreDirectives, f *ast.File, r rule, imports map[string][]string) {
+	for _, spec := range f.Imports {
+		impPath := importPath(spec)
+		if r.matchesPath(impPath) || !r.matchesSpec(spec) {
+			continue
+		}
+		chain := importChain(imports, impPath, r.matchesPath)
+		if chain == nil || usageHasDirective(pass, ignores, spec, spec.Pos(), ignoreKey, directiveTargets(chain[len(chain)-1], "", r.id)...) {
+			continue
+		}
+		msg := fmt.Sprintf("package %q shouldn't be imported, imported through: %s", chain[len(chain)-1], strings.Join(chain, " -> "))
+		if r.sugg != "" {
+			msg += fmt.Sprintf(", suggested: %q", r.sugg)
+		}
+		pass.Reportf(spec.Path.Pos(), msg)
+	}
+}
+
+// importChain returns the shortest chain of import paths from the package
+// with the given import path to a transitive dependency whose path is matched,
+// or nil if there is none.
+func importChain(imports map[string][]string, pkgPath string, match func(string) bool) []string {
+	prev := map[string]string{pkgPath: ""}
+	queue := []string{pkgPath}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if match(p) {
+			var chain []string
+			for ; p != ""; p = prev[p] {
+				chain = append([]string{p}, chain...)
+			}
+			return chain
+		}
+		for _, imp := range imports[p] {
+			if _, ok := prev[imp]; !ok {
+				prev[imp] = p
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return nil
+}
+
 // importSpecs returns all import specs for f import statements importing path.
 func importSpec(f *ast.File, path string, recursive bool) (imports []*ast.ImportSpec) {
 	for _, s := range f.Imports {
@@ -260,74 +783,136 @@ func isTopName(n ast.Expr, name string) bool {
 	return ok && id.Name == name && id.Obj == nil
 }
 
-func parseDirective(pass *analysis.Pass, c *ast.Comment) (option string) {
-	s := c.Text
+// directiveFields returns the option, the faillint name and the reason of the
+// lint directive s, or nil if s is not a faillint lint directive. The name
+// may have targets, i.e: faillint:fmt.Errorf.
+func directiveFields(s string) []string {
 	if !strings.HasPrefix(s, "//lint:") {
-		return ""
+		return nil
 	}
 	s = strings.TrimPrefix(s, "//lint:")
 	fields := strings.SplitN(s, " ", 3)
 
 	if len(fields) < 2 {
-		return ""
+		return nil
 	}
 
-	if fields[1] != "faillint" {
-		return ""
+	if fields[1] != "faillint" && !strings.HasPrefix(fields[1], "faillint:") {
+		return nil
+	}
+	return fields
+}
+
+// parseDirective returns the option of the faillint lint directive c and the
+// targets it's restricted to, if any. Targets are comma separated rule IDs,
+// import paths or declarations in the form of import/path.Name, i.e:
+// //lint:ignore faillint:fmt.Errorf,os reason.
+func parseDirective(pass *analysis.Pass, c *ast.Comment) (option string, targets []string) {
+	fields := directiveFields(c.Text)
+	if fields == nil {
+		return "", nil
 	}
 
-	if fields[0] != ignoreKey && fields[0] != fileIgnoreKey {
+	switch fields[0] {
+	case ignoreKey, fileIgnoreKey, ignoreStartKey, ignoreEndKey:
+	default:
 		pass.Reportf(c.Pos(), unrecognizedOptionTemplate, fields[0])
-		return ""
+		return "", nil
 	}
 
-	if len(fields) < 3 {
+	// The reason is given by the ignore-start directive of a range.
+	if len(fields) < 3 && fields[0] != ignoreEndKey {
 		pass.Reportf(c.Pos(), missingReasonTemplate, fields[0])
-		return ""
+		return "", nil
 	}
 
-	return fields[0]
-}
-
-func anyHasDirective(pass *analysis.Pass, cgs []*ast.CommentGroup, option string) bool {
-	for _, cg := range cgs {
-		if hasDirective(pass, cg, option) {
-			return true
+	if _, list, ok := strings.Cut(fields[1], ":"); ok {
+		if fields[0] != ignoreKey && fields[0] != ignoreStartKey {
+			pass.Reportf(c.Pos(), unsupportedTargetsTemplate, fields[0])
+			return "", nil
 		}
+		targets = strings.Split(list, ",")
 	}
-	return false
+	return fields[0], targets
 }
 
-func hasDirective(pass *analysis.Pass, cg *ast.CommentGroup, option string) bool {
+// hasDirective returns true if any comment of cg is a directive with the
+// given option, which applies to any of the targets. The directive is marked
+// as used.
+func (ig *ignoreDirectives) hasDirective(pass *analysis.Pass, cg *ast.CommentGroup, option string, targets ...string) bool {
 	if cg == nil {
 		return false
 	}
 	for _, c := range cg.List {
-		if parseDirective(pass, c) == option {
-			return true
+		// Range directives are reported by newIgnoreDirectives.
+		if isRangeDirective(c) {
+			continue
+		}
+		opt, dirTargets := parseDirective(pass, c)
+		if opt != option {
+			continue
+		}
+		if !matchTargets(dirTargets, targets) {
+			continue
 		}
+		ig.used[c] = true
+		return true
 	}
 	return false
 }
 
-func usageHasDirective(pass *analysis.Pass, cm ast.CommentMap, n ast.Node, p token.Pos, option string) bool {
-	for _, cg := range cm[n] {
-		if hasDirective(pass, cg, ignoreKey) {
+// hasLineDirective returns true if any comment of cg is a faillint lint
+// directive other than a range directive. It's checked before the more
+// expensive check whether the comment group applies to a problem.
+func hasLineDirective(cg *ast.CommentGroup) bool {
+	return slices.ContainsFunc(cg.List, func(c *ast.Comment) bool {
+		return directiveFields(c.Text) != nil && !isRangeDirective(c)
+	})
+}
+
+// matchTargets returns true if a directive with the given targets applies to a
+// problem with the given targets. Directives without targets apply to all
+// problems.
+func matchTargets(dirTargets, targets []string) bool {
+	return len(dirTargets) == 0 || slices.ContainsFunc(dirTargets, func(t string) bool { return slices.Contains(targets, t) })
+}
+
+// directiveTargets returns the targets of ignore directives, which apply to a
+// problem of the declaration with the given name of the package with the given
+// import path. The name is empty for problems of the package itself and id is
+// the ID of the rule, if any.
+func directiveTargets(impPath, name, id string) []string {
+	targets := []string{impPath}
+	if name != "" {
+		targets = append(targets, impPath+"."+name)
+	}
+	if id != "" {
+		targets = append(targets, id)
+	}
+	return targets
+}
+
+// usageHasDirective returns true if the node n at p, or any node enclosing it,
+// has a directive with the given option, which applies to p and to any of the
+// targets of the problem, or if p is in an ignored range.
+func usageHasDirective(pass *analysis.Pass, ignores *ignoreDirectives, n ast.Node, p token.Pos, option string, targets ...string) bool {
+	for _, cg := range ignores.cm[n] {
+		if hasLineDirective(cg) && ignores.applies(pass.Fset, cg, n, p) && ignores.hasDirective(pass, cg, ignoreKey, targets...) {
 			return true
 		}
 	}
 	// Try to find an "enclosing" node which the ast.CommentMap will
 	// thus have associated comments to this field selector.
-	for node := range cm {
+	for node := range ignores.cm {
 		if p >= node.Pos() && p <= node.End() {
-			for _, cg := range cm[node] {
-				if hasDirective(pass, cg, option) {
+			for _, cg := range ignores.cm[node] {
+				if hasLineDirective(cg) && ignores.applies(pass.Fset, cg, node, p) && ignores.hasDirective(pass, cg, option, targets...) {
 					return true
 				}
 			}
 		}
 	}
-	return false
+	return ignores.inRange(p, targets)
 }
 
 func parsePaths(paths string) []path {
@@ -348,10 +933,38 @@ func parsePaths(paths string) []path {
 				if group[i] == "" {
 					break
 				}
-				p.decls = strings.Split(group[i], ",")
+				p.decls = splitList(group[i], ',')
+			case "options":
+				if group[i] == "" {
+					break
+				}
+				p.opts = splitList(group[i], ',')
 			}
 		}
 		parsed = append(parsed, p)
 	}
 	return parsed
 }
+
+// parseProfiles parses profile definitions in the form of
+// name:paths;name:paths. The paths of a profile only match usages within the
+// functions or files annotated with it.
+func parseProfiles(profiles string) ([]path, error) {
+	profiles = trimAllWhitespaces(profiles)
+	if profiles == "" {
+		return nil, nil
+	}
+
+	var parsed []path
+	for _, def := range splitList(profiles, ';') {
+		name, paths, ok := strings.Cut(def, ":")
+		if !ok || !profileRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid profile %q: must be in the form of name:paths", def)
+		}
+		for _, p := range parsePaths(paths) {
+			p.opts = append(p.opts, "profile="+name)
+			parsed = append(parsed, p)
+		}
+	}
+	return parsed, nil
+}
@@ -3,6 +3,9 @@ package faillint
 import (
 	"fmt"
 	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -42,6 +45,19 @@ func TestParsePaths(t *testing.T) {
 				{imp: "errors"},
 			},
 		},
+		{
+			paths: "name:util,name:helper*",
+			expected: []path{
+				{imp: "name:util"},
+				{imp: "name:helper*"},
+			},
+		},
+		{
+			paths: "services/!$svc/...[in=services/$svc/...]",
+			expected: []path{
+				{imp: "services/!$svc", recursive: true, opts: []string{"in=services/$svc/..."}},
+			},
+		},
 		{
 			// No dedup ):
 			paths: "errors,errors",
@@ -173,6 +189,71 @@ func TestParsePaths(t *testing.T) {
 				},
 			},
 		},
+		{
+			paths: `os.{Getenv(0="AWS_SECRET_ACCESS_KEY"), MkdirAll(1=0777)},regexp.{MustCompile(0=!const)}`,
+			expected: []path{
+				{
+					imp:   "os",
+					decls: []string{`Getenv(0="AWS_SECRET_ACCESS_KEY")`, "MkdirAll(1=0777)"},
+				},
+				{
+					imp:   "regexp",
+					decls: []string{"MustCompile(0=!const)"},
+				},
+			},
+		},
+		{
+			// Whitespace and separators in constraint values are kept.
+			paths: `os.{Getenv(0="A B,C", 1=~/^a{1,2}$/)}=os.{LookupEnv}`,
+			expected: []path{
+				{
+					imp:   "os",
+					decls: []string{`Getenv(0="A B,C",1=~/^a{1,2}$/)`},
+					sugg:  "os.{LookupEnv}",
+				},
+			},
+		},
+		{
+			paths: "fmt.{Print*, Fprint?},os.{/^(Exit|Getenv)$/, /^Must/(0=!const)}",
+			expected: []path{
+				{
+					imp:   "fmt",
+					decls: []string{"Print*", "Fprint?"},
+				},
+				{
+					imp:   "os",
+					decls: []string{"/^(Exit|Getenv)$/", "/^Must/(0=!const)"},
+				},
+			},
+		},
+		{
+			paths: "@go[scope=!test],@global-var=github.com/foo/bar",
+			expected: []path{
+				{
+					imp:  "@go",
+					opts: []string{"scope=!test"},
+				},
+				{
+					imp:  "@global-var",
+					sugg: "github.com/foo/bar",
+				},
+			},
+		},
+		{
+			paths: "net/http/pprof[blank, in=!.../cmd/...]=net/http/pprof.{Handler},database/sql/...[noblank]",
+			expected: []path{
+				{
+					imp:  "net/http/pprof",
+					opts: []string{"blank", "in=!.../cmd/..."},
+					sugg: "net/http/pprof.{Handler}",
+				},
+				{
+					imp:       "database/sql",
+					recursive: true,
+					opts:      []string{"noblank"},
+				},
+			},
+		},
 	} {
 		t.Run("", func(t *testing.T) {
 			equals(t, tcase.expected, parsePaths(tcase.paths))
@@ -180,6 +261,260 @@ func TestParsePaths(t *testing.T) {
 	}
 }
 
+func TestParseDecl(t *testing.T) {
+	for _, tcase := range []struct {
+		decl     string
+		name     string
+		args     int
+		expError bool
+	}{
+		{decl: "Errorf", name: "Errorf"},
+		{decl: `Getenv(0="AWS_SECRET_ACCESS_KEY")`, name: "Getenv", args: 1},
+		{decl: "MkdirAll(0=const,1=0777)", name: "MkdirAll", args: 2},
+		{decl: "MustCompile(0=!const)", name: "MustCompile", args: 1},
+		{decl: "Getenv(0=~/^AWS_/)", name: "Getenv", args: 1},
+		{decl: "Sleep(0=-1)", name: "Sleep", args: 1},
+		{decl: "Print*", name: "Print*"},
+		{decl: "/^Must/", name: "/^Must/"},
+		{decl: `/^(Exit|Must\/)$/(0=1)`, name: `/^(Exit|Must\/)$/`, args: 1},
+		{decl: "Print*(0=const)", name: "Print*", args: 1},
+		{decl: "LDFLAGS(*=~/^-l/)", name: "LDFLAGS", args: 1},
+		{decl: "/^Must", expError: true},
+		{decl: "/(/", expError: true},
+		{decl: "/^Must/0=const", expError: true},
+		{decl: "Getenv(0=~/[/)", expError: true},
+		{decl: "Getenv(0=~^AWS_)", expError: true},
+		{decl: "Getenv(a=const)", expError: true},
+		{decl: "Getenv(0)", expError: true},
+		{decl: "Getenv(**=const)", expError: true},
+		{decl: "Getenv(0=foo)", expError: true},
+		{decl: `Getenv(0=-"foo")`, expError: true},
+	} {
+		t.Run(tcase.decl, func(t *testing.T) {
+			d, err := parseDecl(tcase.decl)
+			if tcase.expError {
+				if err == nil {
+					t.Fatalf("expected error for %q", tcase.decl)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			equals(t, tcase.name, d.name)
+			equals(t, tcase.args, len(d.args))
+		})
+	}
+}
+
+func TestCompileRulesErrors(t *testing.T) {
+	for _, paths := range []string{
+		"fmt.{Errorf}[foo]",
+		"net/http/pprof[blank=true]",
+		"net/http/pprof[blank,noblank]",
+		"time.{Sleep}[scope=tests]",
+		"time.{Sleep}[func=^Test]",
+		"time.{Sleep}[func=/(/]",
+		"builtin",
+		"net/http.{Get}[transitive]",
+		"net/http[transitive,scope=test]",
+		"errors[alias=1errs]",
+		"errors.{New}[alias=errs]",
+		"time/tzdata[required,noblank]",
+		"time/tzdata[required,scope=test]",
+		"time/tzdata[pkgname=1main]",
+		"@goroutine",
+		"@go.{Foo}",
+		"context.{TODO}[implements=Handler]",
+		"context.{TODO}[implements=net/http.]",
+		"os.{Getenv(0=foo)}",
+		"name:util[transitive]",
+		"name:util[scope=test]",
+		"name:util.{Foo}",
+		"services/!$svc/...",
+		"services/!$svc/...[in=cmd/$app/...]",
+		"services/!$svc/...[in=!services/$svc/...]",
+		"services/!$svc/...[in=services/$svc/...,transitive]",
+		"services/!svc/...[in=services/$svc/...]",
+		"services/$svc/...[in=!$svc/...]",
+		"time.{Now}[profile=]",
+		"@go:",
+		"@go:linkname[transitive]",
+		"@go:linkname.{embed}",
+		"@go:{linkname}[blank]",
+		"time.{Now}[profile=hot.path]",
+		"reflect[profile=hotpath,transitive]",
+		"fmt.{Errorf}[id=no.errorf]",
+	} {
+		t.Run(paths, func(t *testing.T) {
+			if _, err := compileRules(parsePaths(paths)); err == nil {
+				t.Fatalf("expected error for %q", paths)
+			}
+		})
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	parsed, err := parseProfiles("hotpath: fmt.{Sprintf}, reflect; alloc:strings.{Join}[in=foo/...]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equals(t, []path{
+		{imp: "fmt", decls: []string{"Sprintf"}, opts: []string{"profile=hotpath"}},
+		{imp: "reflect", opts: []string{"profile=hotpath"}},
+		{imp: "strings", decls: []string{"Join"}, opts: []string{"in=foo/...", "profile=alloc"}},
+	}, parsed)
+
+	for _, profiles := range []string{"fmt.{Sprintf}", "hot.path:reflect"} {
+		if _, err := parseProfiles(profiles); err == nil {
+			t.Errorf("expected error for %q", profiles)
+		}
+	}
+}
+
+func TestFactTypes(t *testing.T) {
+	a := NewAnalyzer()
+	a.Flags.Set("paths", "errors")
+	equals(t, 0, len(a.FactTypes), "facts shouldn't be used without a check using them")
+
+	a.Flags.Set("deprecated", "true")
+	a.Flags.Set("banned", "true")
+	equals(t, []analysis.Fact{new(deprecatedFact), new(bannedFact)}, a.FactTypes)
+
+	a.Flags.Set("deprecated", "false")
+	equals(t, []analysis.Fact{new(bannedFact)}, a.FactTypes)
+
+	a.Flags.Set("banned", "false")
+	a.Flags.Set("paths", "net/http[transitive]")
+	equals(t, []analysis.Fact{new(importsFact)}, a.FactTypes)
+}
+
+func TestModuleOf(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"pkg", "vendor/example.com/dep", "src/foo"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gomod := "// Deprecated: Use other instead.\nmodule example.com/main\n"
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tcase := range []struct {
+		dir, pkgPath string
+		found        bool
+	}{
+		{dir: ".", pkgPath: "example.com/main", found: true},
+		{dir: "pkg", pkgPath: "example.com/main/pkg", found: true},
+		{dir: "vendor/example.com/dep", pkgPath: "example.com/dep"},
+		{dir: "vendor/example.com/dep", pkgPath: "example.com/main/vendor/example.com/dep"},
+		{dir: "src/foo", pkgPath: "foo"},
+	} {
+		t.Run(tcase.pkgPath, func(t *testing.T) {
+			mod := moduleOf(filepath.Join(root, tcase.dir), tcase.pkgPath)
+			equals(t, tcase.found, mod != nil)
+		})
+	}
+}
+
+func TestSplitForbid(t *testing.T) {
+	for _, tcase := range []struct {
+		args   string
+		spec   string
+		reason string
+	}{
+		{args: " reflect", spec: "reflect"},
+		{args: " fmt.{Print*} no printing  in the hot path ", spec: "fmt.{Print*}", reason: "no printing  in the hot path"},
+		{args: "\tos.{Getenv(0=\"A B\")}\tsecrets", spec: `os.{Getenv(0="A B")}`, reason: "secrets"},
+		{args: "", spec: ""},
+	} {
+		t.Run(tcase.args, func(t *testing.T) {
+			spec, reason := splitForbid(tcase.args)
+			equals(t, tcase.spec, spec)
+			equals(t, tcase.reason, reason)
+		})
+	}
+}
+
+func TestFileDirectives(t *testing.T) {
+	src := `package cgo
+
+// #cgo CFLAGS: -DPNG_DEBUG=1
+// #cgo linux,amd64 LDFLAGS: -lpng -lz
+// #include <png.h>
+import "C"
+
+/*
+#cgo pkg-config: png
+*/
+import "C"
+
+//go:noinline
+func f() {}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "cgo.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range fileDirectives(f) {
+		got = append(got, fmt.Sprintf("%d:%d %s %v", fset.Position(d.pos).Line, fset.Position(d.pos).Column, d, d.args))
+	}
+	equals(t, []string{
+		"13:1 //go:noinline []",
+		"3:4 #cgo CFLAGS [-DPNG_DEBUG=1]",
+		"4:4 #cgo LDFLAGS [-lpng -lz]",
+		"9:1 #cgo pkg-config [png]",
+	}, got)
+}
+
+func TestSplitArgs(t *testing.T) {
+	for _, tcase := range []struct {
+		s    string
+		args []string
+	}{
+		{s: "", args: nil},
+		{s: " generate  go run\tgen.go", args: []string{"generate", "go", "run", "gen.go"}},
+		{s: `generate stringer -type "My Type" -linecomment`, args: []string{"generate", "stringer", "-type", "My Type", "-linecomment"}},
+		{s: `generate echo "a \" b" ` + "`c d`", args: []string{"generate", "echo", `a " b`, "c d"}},
+		{s: `embed "unterminated quote`, args: []string{"embed", `"unterminated`, "quote"}},
+	} {
+		t.Run(tcase.s, func(t *testing.T) {
+			equals(t, tcase.args, splitArgs(tcase.s))
+		})
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	for _, tcase := range []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{pattern: "net/http", path: "net/http", match: true},
+		{pattern: "net/http", path: "net/http/pprof", match: false},
+		{pattern: "net/...", path: "net", match: true},
+		{pattern: "net/...", path: "net/http/pprof", match: true},
+		{pattern: "net/...", path: "network", match: false},
+		{pattern: ".../cmd/...", path: "github.com/foo/bar/cmd/server", match: true},
+		{pattern: ".../cmd/...", path: "github.com/foo/bar/internal/cmdutil", match: false},
+		{pattern: "!.../cmd/...", path: "github.com/foo/bar/cmd", match: true},
+		{pattern: "services/$svc/...", path: "services/auth/api", match: true},
+		{pattern: "services/$svc/...", path: "services", match: false},
+	} {
+		t.Run(tcase.pattern+" "+tcase.path, func(t *testing.T) {
+			p, err := compilePattern(tcase.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equals(t, tcase.match, p.re.MatchString(tcase.path))
+		})
+	}
+}
+
 func TestRun(t *testing.T) {
 	testdata := analysistest.TestData()
 
@@ -190,6 +525,14 @@ func TestRun(t *testing.T) {
 
 		ignoreTestFiles   bool
 		onlyTestFunctions bool
+		deprecated        bool
+		deprecatedAllow   string
+		wrappers          bool
+		visibility        bool
+		banned            bool
+		ignoreEnclosing   bool
+		profiles          string
+		suggestedFixes    bool
 	}{
 		{
 			name:              "sleep in a function which is not a test",
@@ -223,6 +566,11 @@ func TestRun(t *testing.T) {
 			dir:   "a_all",
 			paths: "errors",
 		},
+		{
+			name:  "unwanted errors.New function used through dot import",
+			dir:   "a_dot",
+			paths: "errors.{New}",
+		},
 		{
 			name:  "unwanted errors package under different names",
 			dir:   "a_many_names",
@@ -264,6 +612,11 @@ func TestRun(t *testing.T) {
 			dir:   "g_complex",
 			paths: "fmt.{Errorf}=github.com/pkg/errors.{Errorf},fmt.{Println,Print,Printf}",
 		},
+		{
+			name:  "unwanted functions matched by glob and regexp",
+			dir:   "g_pattern",
+			paths: "fmt.{Print*,/^Sprint(f|ln)$/},os.{/^Exit$/}=github.com/foo/bar.{Exit}",
+		},
 		{
 			name:  "unwanted functions with package rename",
 			dir:   "g_with_name",
@@ -326,6 +679,120 @@ func TestRun(t *testing.T) {
 			dir:   "q",
 			paths: "errors",
 		},
+		{
+			name:  "unwanted blank imports outside of cmd",
+			dir:   "blank/...",
+			paths: "net/http/pprof[blank,in=!.../cmd/...]=net/http/pprof.{Handler},net/http[noblank,in=blank/cmd/...],database/sql[noblank],errors[blank]",
+		},
+		{
+			name:  "unwanted usages scoped by enclosing declaration",
+			dir:   "scope",
+			paths: "time.{Sleep}[scope=test|benchmark],regexp.{MustCompile}[scope=!package],log.{Fatal}[scope=!main],net/http[scope=init],os.{Getenv}[scope=exported,func=!/^New/],strings.{ToUpper}[scope=fuzz|example]",
+		},
+		{
+			name:  "unwanted usages in methods implementing an interface",
+			dir:   "implements",
+			paths: "context.{Background,TODO}[implements=net/http.Handler,func=/^ServeHTTP$/]=r.Context,context.{Background,TODO}[implements=implements.GreeterServer]",
+		},
+		{
+			name:  "unwanted language constructs",
+			dir:   "constructs",
+			paths: "@go[scope=!test]=errgroup.Group.Go,@goto,@init,@recover,@global-var,@select",
+		},
+		{
+			name:  "unwanted builtin functions",
+			dir:   "builtins",
+			paths: "builtin.{println,print,panic,Sizeof}=log.Println",
+		},
+		{
+			name:            "usages of deprecated declarations",
+			dir:             "deprecated/user",
+			deprecated:      true,
+			deprecatedAllow: "strings.{Title}",
+		},
+		{
+			name:     "unwanted functions called through wrappers of other packages",
+			dir:      "example.com/wrappers/app",
+			paths:    "os.{Exit},fmt.{Println}[in=!example.com/wrappers/app],fmt.{Sprint}[in=example.com/wrappers/app],example.com/wrappers/util.{Kill}",
+			wrappers: true,
+		},
+		{
+			name:  "unwanted transitive imports",
+			dir:   "example.com/transitive/sdk",
+			paths: "net/http[transitive]",
+		},
+		{
+			name:           "import aliases",
+			dir:            "alias",
+			paths:          "encoding/json[alias=stdjson],errors[noalias],net/http[alias=!nethttp,alias=!h]=http,strings[alias=stdstrings],time[noalias],fmt[alias=fmt],strconv[noalias],path/filepath[noalias],net/url[alias=path]",
+			suggestedFixes: true,
+		},
+		{
+			name:  "required imports",
+			dir:   "example.com/required/...",
+			paths: "time/tzdata[required,blank,pkgname=main],example.com/required/buildinfo[required,in=.../cmd/...]=example.com/required/buildinfo.{Version}",
+		},
+		{
+			name:  "package names",
+			dir:   "example.com/names/app",
+			paths: "name:util,name:helper*,name:common=example.com/names/shared,name:string?",
+		},
+		{
+			name:  "capture variables",
+			dir:   "example.com/services/...",
+			paths: "example.com/services/services/!$svc/...[in=example.com/services/services/$svc/...]",
+		},
+		{
+			name:       "declared package visibility",
+			dir:        "example.com/visibility/services/...",
+			visibility: true,
+		},
+		{
+			name:   "declarations banned with directives",
+			dir:    "example.com/banned/app",
+			banned: true,
+		},
+		{
+			name: "package-local rules of forbid directives",
+			dir:  "example.com/forbid/...",
+		},
+		{
+			name:     "rule profiles",
+			dir:      "profiles",
+			paths:    "strings.{Split}[profile=alloc]",
+			profiles: "hotpath:fmt.{Sprintf},reflect,time.{Now}=time.{Since};alloc:strings.{Join}",
+		},
+		{
+			name:  "compiler directives",
+			dir:   "directives",
+			paths: `@go:linkname=time.Now,@go:{debug(0=~/^panicnil=/)},@cgo:{LDFLAGS(*=~/^-l/)},@generate:mockgen=go.uber.org/mock/mockgen,@generate:{go(*=~/@latest$/),stringer(2="My"),chmod(0=0777)}`,
+		},
+		{
+			name:  "unused directives",
+			dir:   "unused",
+			paths: "errors.{New},fmt.{Errorf}",
+		},
+		{
+			name:  "ignore directives with targets",
+			dir:   "ignoretargets",
+			paths: "errors.{New},fmt.{Errorf},fmt.{Sprintf}[id=no-sprintf]",
+		},
+		{
+			name:  "scope of ignore directives",
+			dir:   "ignorescope",
+			paths: "errors.{New},fmt.{Errorf},io.{Reader}",
+		},
+		{
+			name:            "ignore directives applying to enclosing nodes",
+			dir:             "ignoreenclosing",
+			paths:           "errors.{New}",
+			ignoreEnclosing: true,
+		},
+		{
+			name:  "unwanted functions with argument constraints",
+			dir:   "r",
+			paths: `os.{Getenv(0="AWS_SECRET_ACCESS_KEY"),Getenv(0=~/^AWS_SESSION/),MkdirAll(1=0777)},regexp.{MustCompile(0=!const)},path/filepath.{Join(*="..")}`,
+		},
 	} {
 		t.Run(tcase.name, func(t *testing.T) {
 			f := NewAnalyzer()
@@ -336,9 +803,31 @@ func TestRun(t *testing.T) {
 			if tcase.onlyTestFunctions {
 				f.Flags.Set("only-tests", "true")
 			}
+			if tcase.wrappers {
+				f.Flags.Set("wrappers", "true")
+			}
+			if tcase.visibility {
+				f.Flags.Set("visibility", "true")
+			}
+			if tcase.banned {
+				f.Flags.Set("banned", "true")
+			}
+			if tcase.ignoreEnclosing {
+				f.Flags.Set("ignore-enclosing", "true")
+			}
+			f.Flags.Set("profiles", tcase.profiles)
+			if tcase.deprecated {
+				f.Flags.Set("deprecated", "true")
+				f.Flags.Set("deprecated-allow", tcase.deprecatedAllow)
+			}
 
 			// No assertion on result is required as 'analysistest' is for that.
 			// All expected diagnosis should be specified by comment in affected file starting with `// want`.
+			if tcase.suggestedFixes {
+				// Suggested fixes are compared against the .golden files.
+				_ = analysistest.RunWithSuggestedFixes(t, testdata, f, tcase.dir)
+				return
+			}
 			_ = analysistest.Run(t, testdata, f, tcase.dir)
 		})
 	}
@@ -348,6 +837,7 @@ func TestHasDirective(t *testing.T) {
 	type input struct {
 		comments []*ast.Comment
 		option   string
+		targets  []string
 	}
 	type expected struct {
 		out     bool
@@ -408,6 +898,45 @@ func TestHasDirective(t *testing.T) {
 				out: true,
 			},
 		},
+		{
+			name: "ignore with matching target",
+			input: input{
+				comments: []*ast.Comment{
+					{Text: "//lint:ignore faillint:fmt.Errorf,no-sprintf reason"},
+				},
+				option:  ignoreKey,
+				targets: []string{"fmt", "fmt.Sprintf", "no-sprintf"},
+			},
+			expected: expected{
+				out: true,
+			},
+		},
+		{
+			name: "ignore with other target",
+			input: input{
+				comments: []*ast.Comment{
+					{Text: "//lint:ignore faillint:fmt.Errorf reason"},
+				},
+				option:  ignoreKey,
+				targets: []string{"errors", "errors.New"},
+			},
+			expected: expected{
+				out: false,
+			},
+		},
+		{
+			name: "targets on file-ignore",
+			input: input{
+				comments: []*ast.Comment{
+					{Text: "//lint:file-ignore faillint:fmt reason"},
+				},
+				option: fileIgnoreKey,
+			},
+			expected: expected{
+				out:     false,
+				message: fmt.Sprintf(unsupportedTargetsTemplate, "file-ignore"),
+			},
+		},
 		{
 			name: "invalid option on faillint directive",
 			input: input{
@@ -429,7 +958,8 @@ func TestHasDirective(t *testing.T) {
 					diagnostic = d
 				},
 			}
-			got := hasDirective(&pass, &ast.CommentGroup{List: tcase.input.comments}, tcase.input.option)
+			ignores := &ignoreDirectives{used: make(map[*ast.Comment]bool)}
+			got := ignores.hasDirective(&pass, &ast.CommentGroup{List: tcase.input.comments}, tcase.input.option, tcase.input.targets...)
 			if got != tcase.expected.out {
 				t.Errorf("expected hasDirective to return %v, got %v", tcase.expected.out, got)
 			}
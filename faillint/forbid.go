@@ -0,0 +1,80 @@
+package faillint
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// forbidKey is the directive adding rules for the package it's declared in,
+// i.e: //faillint:forbid fmt.{Print*} no printing in the hot path
+const forbidKey = "//faillint:forbid"
+
+// forbidRules returns the rules declared by the forbid directives of the
+// package. The rule spec has the same format as the -paths flag and the
+// remaining text of the directive is the reason, which is printed as the
+// suggestion. Invalid directives are reported.
+func forbidRules(pass *analysis.Pass) []rule {
+	var rules []rule
+	for _, file := range pass.Files {
+		for _, cg := range file.Comments {
+			for _, c := range cg.List {
+				args, ok := strings.CutPrefix(c.Text, forbidKey)
+				if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+					continue
+				}
+				spec, reason := splitForbid(args)
+				if spec == "" {
+					pass.Reportf(c.Pos(), "invalid forbid directive: missing rule")
+					continue
+				}
+				paths := parsePaths(spec)
+				if len(paths) == 0 || !parsedWhole(spec) {
+					pass.Reportf(c.Pos(), "invalid forbid directive: invalid rule %q", spec)
+					continue
+				}
+				for i := range paths {
+					switch {
+					case reason == "":
+					case paths[i].sugg == "":
+						paths[i].sugg = reason
+					default:
+						paths[i].sugg += ": " + reason
+					}
+				}
+				compiled, err := compileRules(paths)
+				if err != nil {
+					pass.Reportf(c.Pos(), "invalid forbid directive: %v", err)
+					continue
+				}
+				rules = append(rules, compiled...)
+			}
+		}
+	}
+	return rules
+}
+
+// parsedWhole returns true if the paths parsed from spec cover all of it. As
+// parsePaths skips the text it can't parse, a typo would change the rule
+// silently otherwise.
+func parsedWhole(spec string) bool {
+	spec = trimAllWhitespaces(spec)
+	end := 0
+	for _, loc := range pathsRegexp.FindAllStringIndex(spec, -1) {
+		// The paths are separated by a single comma.
+		if loc[0] != end && (end == 0 || loc[0] != end+1 || spec[end] != ',') {
+			return false
+		}
+		end = loc[1]
+	}
+	return end == len(spec)
+}
+
+// splitForbid splits the arguments of a forbid directive into the rule spec
+// and the reason. Spaces in quotes, parentheses and braces of the rule spec
+// don't end it.
+func splitForbid(args string) (spec, reason string) {
+	args = strings.TrimSpace(strings.ReplaceAll(args, "\t", " "))
+	spec = splitList(args, ' ')[0]
+	return spec, strings.TrimSpace(args[len(spec):])
+}
@@ -0,0 +1,149 @@
+package faillint
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ignoreDirectives contains the lint directives of a file, which can suppress
+// its problems.
+type ignoreDirectives struct {
+	cm     ast.CommentMap
+	ranges []ignoreRange
+
+	// used contains the lint directives of the package, which suppressed a
+	// problem or were reported otherwise.
+	used map[*ast.Comment]bool
+
+	// enclosing is true if line-based directives apply to all problems within
+	// the nodes they're attached to.
+	enclosing bool
+
+	// problems contains the problems of the ignore-start and ignore-end
+	// directives, such as directives without a matching directive.
+	problems []analysis.Diagnostic
+}
+
+// ignoreRange is a range of ignored problems, started by an ignore-start
+// directive and ended by an ignore-end directive.
+type ignoreRange struct {
+	start   *ast.Comment
+	end     token.Pos
+	targets []string
+}
+
+// newIgnoreDirectives returns the lint directives of f. Line-based directives
+// apply to all problems within the nodes they're attached to if enclosing is
+// true. The directives suppressing a problem are added to used.
+func newIgnoreDirectives(pass *analysis.Pass, f *ast.File, enclosing bool, used map[*ast.Comment]bool) *ignoreDirectives {
+	ignores := &ignoreDirectives{
+		cm:        ast.NewCommentMap(pass.Fset, f, f.Comments),
+		used:      used,
+		enclosing: enclosing,
+	}
+
+	// The problems are collected to be reported only once per file.
+	collect := *pass
+	collect.Report = func(d analysis.Diagnostic) {
+		ignores.problems = append(ignores.problems, d)
+	}
+
+	var open *ignoreRange
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if !isRangeDirective(c) {
+				continue
+			}
+			opt, targets := parseDirective(&collect, c)
+			switch {
+			case opt == ignoreStartKey && open != nil:
+				// Skipped directives are reported already, not as unused ones.
+				ignores.used[c] = true
+				collect.Reportf(c.Pos(), "faillint:ignore-start directive within the range of another one")
+			case opt == ignoreStartKey:
+				open = &ignoreRange{start: c, targets: targets}
+			case opt == ignoreEndKey && open == nil:
+				collect.Reportf(c.Pos(), "faillint:ignore-end directive without faillint:ignore-start directive")
+			case opt == ignoreEndKey:
+				open.end = c.Pos()
+				ignores.ranges = append(ignores.ranges, *open)
+				open = nil
+			}
+		}
+	}
+	if open != nil {
+		ignores.used[open.start] = true
+		collect.Reportf(open.start.Pos(), "faillint:ignore-start directive without faillint:ignore-end directive")
+	}
+	return ignores
+}
+
+// isRangeDirective returns true if c is an ignore-start or ignore-end
+// directive.
+func isRangeDirective(c *ast.Comment) bool {
+	fields := directiveFields(c.Text)
+	return fields != nil && (fields[0] == ignoreStartKey || fields[0] == ignoreEndKey)
+}
+
+// reportProblems reports the problems of the ignore-start and ignore-end
+// directives. Invalid directives and directives without a matching directive
+// are skipped.
+func (ig *ignoreDirectives) reportProblems(pass *analysis.Pass) {
+	for _, d := range ig.problems {
+		pass.Report(d)
+	}
+}
+
+// inRange returns true if p is in an ignored range, whose ignore-start
+// directive applies to any of the targets.
+func (ig *ignoreDirectives) inRange(p token.Pos, targets []string) bool {
+	for _, r := range ig.ranges {
+		if p > r.start.End() && p < r.end && matchTargets(r.targets, targets) {
+			ig.used[r.start] = true
+			return true
+		}
+	}
+	return false
+}
+
+// applies returns true if the line-based directives of the comment group cg,
+// which is attached to node, apply to a problem at p. They apply to the lines
+// of cg and the line after it and, if cg is directly above node, to the whole
+// node, except for the bodies of its nested blocks. For example, a directive
+// above a function declaration applies to its signature, but not to its body.
+func (ig *ignoreDirectives) applies(fset *token.FileSet, cg *ast.CommentGroup, node ast.Node, p token.Pos) bool {
+	if ig.enclosing {
+		return true
+	}
+	line := fset.Position(p).Line
+	if line >= fset.Position(cg.Pos()).Line && line <= fset.Position(cg.End()).Line+1 {
+		return true
+	}
+	if _, ok := node.(*ast.File); ok || cg.End() > node.Pos() || fset.Position(node.Pos()).Line > fset.Position(cg.End()).Line+1 {
+		return false
+	}
+	return !inNestedBody(node, p)
+}
+
+// inNestedBody returns true if p is in a block or the body of a case clause
+// nested in node.
+func inNestedBody(node ast.Node, p token.Pos) bool {
+	nested := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if nested || n == nil || p < n.Pos() || p >= n.End() {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			nested = n != node
+		case *ast.CaseClause:
+			nested = p > n.Colon
+		case *ast.CommClause:
+			nested = p > n.Colon
+		}
+		return !nested
+	})
+	return nested
+}
@@ -0,0 +1,438 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// rule is a path compiled to be matched against the analyzed files.
+type rule struct {
+	path
+
+	// parsedDecls contains the parsed declarations of the path.
+	parsedDecls []decl
+
+	// construct is set if the path disallows a Go language construct
+	// instead of an import path, i.e: @go.
+	construct string
+
+	// directive is set to the kind of the directives the path disallows
+	// instead of an import path, i.e: go for @go:linkname.
+	directive string
+
+	// importedName is set if the path disallows imported packages by their
+	// package name instead of their import path, i.e: name:util. It's a
+	// glob pattern.
+	importedName string
+
+	// blank is true if only blank imports of the path should be matched.
+	blank bool
+
+	// noBlank is true if blank imports of the path should not be matched.
+	noBlank bool
+
+	// in contains the patterns of the importing packages the rule applies to.
+	in []pattern
+
+	// scopes restricts the rule to usages within certain enclosing
+	// declarations. All scopes must match.
+	scopes []scope
+
+	// transitive is true if imports depending on the path through any of
+	// their transitive dependencies should be matched as well.
+	transitive bool
+
+	// alias is the alias the path must be imported with.
+	alias string
+
+	// forbiddenAliases contains the aliases the path must not be imported
+	// with.
+	forbiddenAliases []string
+
+	// noAlias is true if the path must not be imported with an alias.
+	noAlias bool
+
+	// pkgNames contains the names of the importing packages the rule
+	// applies to.
+	pkgNames []string
+
+	// required is true if the path must be imported by the packages the
+	// rule applies to.
+	required bool
+
+	// id is the ID of the rule, which can be used as the target of ignore
+	// directives, i.e: //lint:ignore faillint:no-errorf reason.
+	id string
+
+	// impPattern is set if the import path contains capture variables, i.e:
+	// services/!$svc/... . The variables are bound by the in patterns.
+	impPattern *pattern
+}
+
+// compileRules compiles paths into rules.
+func compileRules(paths []path) ([]rule, error) {
+	rules := make([]rule, 0, len(paths))
+	for _, p := range paths {
+		r := rule{path: p}
+		if name, ok := strings.CutPrefix(p.imp, "name:"); ok {
+			if _, err := filepath.Match(name, ""); err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", p.imp, err)
+			}
+			r.importedName = name
+		}
+		for _, kind := range directiveKinds {
+			name, ok := strings.CutPrefix(p.imp, "@"+kind+":")
+			if !ok {
+				continue
+			}
+			r.directive = kind
+			switch {
+			case name != "" && len(p.decls) > 0:
+				return nil, fmt.Errorf("invalid path %q: directive names must be listed either after the colon or as declarations", p.imp)
+			case name != "":
+				p.decls = []string{name}
+				r.decls = p.decls
+			case len(p.decls) == 0:
+				return nil, fmt.Errorf("invalid path %q: missing directive name", p.imp)
+			}
+		}
+		if strings.HasPrefix(p.imp, "@") && r.directive == "" {
+			r.construct = p.imp[1:]
+			if _, ok := constructs[r.construct]; !ok {
+				return nil, fmt.Errorf("invalid path %q: unknown construct %q", p.imp, r.construct)
+			}
+			if len(p.decls) > 0 || p.recursive {
+				return nil, fmt.Errorf("invalid path %q: constructs cannot have declarations", p.imp)
+			}
+		}
+		for _, d := range p.decls {
+			parsed, err := parseDecl(d)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", p.imp, err)
+			}
+			r.parsedDecls = append(r.parsedDecls, parsed)
+		}
+		for _, opt := range p.opts {
+			if err := r.setOption(opt); err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", p.imp, err)
+			}
+		}
+		if r.blank && r.noBlank {
+			return nil, fmt.Errorf("invalid path %q: blank and noblank options cannot be used together", p.imp)
+		}
+		if r.directive != "" && (p.recursive || r.blank || r.noBlank || r.transitive || r.isAliasRule() || r.required) {
+			return nil, fmt.Errorf("invalid path %q: directives can only be used with the in, pkgname and scope options", p.imp)
+		}
+		if r.importedName != "" && (len(p.decls) > 0 || p.recursive || len(r.scopes) > 0 || r.transitive || r.isAliasRule() || r.required) {
+			return nil, fmt.Errorf("invalid path %q: package names can only be used without declarations and scopes", p.imp)
+		}
+		if r.transitive && (len(p.decls) > 0 || len(r.scopes) > 0 || r.construct != "") {
+			return nil, fmt.Errorf("invalid path %q: transitive option can only be used for import paths without declarations and scopes", p.imp)
+		}
+		if r.isAliasRule() && (len(p.decls) > 0 || len(r.scopes) > 0 || r.transitive || r.construct != "") {
+			return nil, fmt.Errorf("invalid path %q: alias options can only be used for import paths without declarations and scopes", p.imp)
+		}
+		if r.required && (len(p.decls) > 0 || len(r.scopes) > 0 || r.transitive || r.isAliasRule() || r.noBlank || r.construct != "") {
+			return nil, fmt.Errorf("invalid path %q: required option can only be used for import paths without declarations and scopes", p.imp)
+		}
+		if strings.ContainsAny(p.imp, "$!") {
+			if err := r.compileImportPattern(); err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", p.imp, err)
+			}
+		}
+		if p.imp == builtinPath && (len(p.decls) == 0 || p.recursive) {
+			return nil, fmt.Errorf("invalid path %q: builtin functions must be listed as declarations", p.imp)
+		}
+		if len(r.scopes) > 0 && len(r.parsedDecls) == 0 && r.construct == "" {
+			// A scoped path matches all usages of the package within the scope.
+			r.parsedDecls = []decl{{name: "*"}}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// setOption parses a single option in the form of key or key=value and sets
+// it on r.
+func (r *rule) setOption(opt string) error {
+	key, value, hasValue := strings.Cut(opt, "=")
+	switch key {
+	case "blank", "noblank":
+		if hasValue {
+			return fmt.Errorf("option %q doesn't accept a value", key)
+		}
+		r.blank = r.blank || key == "blank"
+		r.noBlank = r.noBlank || key == "noblank"
+	case "alias":
+		name, forbidden := strings.CutPrefix(value, "!")
+		if !token.IsIdentifier(name) || name == "_" {
+			return fmt.Errorf("option %q: invalid alias %q", opt, name)
+		}
+		if forbidden {
+			r.forbiddenAliases = append(r.forbiddenAliases, name)
+		} else {
+			r.alias = name
+		}
+	case "noalias":
+		if hasValue {
+			return fmt.Errorf("option %q doesn't accept a value", key)
+		}
+		r.noAlias = true
+	case "pkgname":
+		if !token.IsIdentifier(value) {
+			return fmt.Errorf("option %q: invalid package name %q", opt, value)
+		}
+		r.pkgNames = append(r.pkgNames, value)
+	case "required":
+		if hasValue {
+			return fmt.Errorf("option %q doesn't accept a value", key)
+		}
+		r.required = true
+	case "transitive":
+		if hasValue {
+			return fmt.Errorf("option %q doesn't accept a value", key)
+		}
+		r.transitive = true
+	case "id":
+		if !profileRegexp.MatchString(value) {
+			return fmt.Errorf("option %q: invalid rule ID %q", opt, value)
+		}
+		r.id = value
+	case "in":
+		pat, err := compilePattern(value)
+		if err != nil {
+			return fmt.Errorf("option %q: %v", opt, err)
+		}
+		r.in = append(r.in, pat)
+	case "scope":
+		s, err := parseScope(value)
+		if err != nil {
+			return fmt.Errorf("option %q: %v", opt, err)
+		}
+		r.scopes = append(r.scopes, s)
+	case "implements":
+		s, err := parseImplementsScope(value)
+		if err != nil {
+			return fmt.Errorf("option %q: %v", opt, err)
+		}
+		r.scopes = append(r.scopes, s)
+	case "profile":
+		s, err := parseProfileScope(value)
+		if err != nil {
+			return fmt.Errorf("option %q: %v", opt, err)
+		}
+		r.scopes = append(r.scopes, s)
+	case "func":
+		s, err := parseFuncScope(value)
+		if err != nil {
+			return fmt.Errorf("option %q: %v", opt, err)
+		}
+		r.scopes = append(r.scopes, s)
+	default:
+		return fmt.Errorf("unknown option %q", opt)
+	}
+	return nil
+}
+
+// appliesTo returns true if the rule applies to the package. A package must
+// match at least one of the including patterns, if any, and none of the
+// excluding patterns. If package names are given, the package must have any
+// of them.
+func (r *rule) appliesTo(pkg *types.Package) bool {
+	if len(r.pkgNames) > 0 && !slices.Contains(r.pkgNames, pkg.Name()) {
+		return false
+	}
+
+	pkgPath := pkg.Path()
+	included, hasIncludes := false, false
+	for _, pat := range r.in {
+		if pat.negate {
+			if pat.re.MatchString(pkgPath) {
+				return false
+			}
+			continue
+		}
+		hasIncludes = true
+		included = included || pat.re.MatchString(pkgPath)
+	}
+	return included || !hasIncludes
+}
+
+// inScope returns true if the usage at pos is within all scopes of the rule.
+func (r *rule) inScope(pass *analysis.Pass, file *ast.File, pos token.Pos) bool {
+	for _, s := range r.scopes {
+		if !s.match(pass, file, pos) {
+			return false
+		}
+	}
+	return true
+}
+
+// compileImportPattern compiles the import path containing capture variables.
+// All variables must be bound by an including in pattern.
+func (r *rule) compileImportPattern() error {
+	if r.transitive || r.required || r.construct != "" || r.importedName != "" {
+		return fmt.Errorf("capture variables can only be used for import paths")
+	}
+	if strings.Count(r.imp, "!") != strings.Count(r.imp, "!$") {
+		return fmt.Errorf("only capture variables can be negated")
+	}
+
+	re, vars := compileVars(regexp.QuoteMeta(r.imp))
+	if r.recursive {
+		re += `(/.*)?`
+	}
+	pat := pattern{vars: vars}
+	var err error
+	if pat.re, err = regexp.Compile(`^` + re + `$`); err != nil {
+		return err
+	}
+
+	for _, v := range vars {
+		bound := slices.ContainsFunc(r.in, func(p pattern) bool {
+			return !p.negate && slices.ContainsFunc(p.vars, func(w patternVar) bool { return w.name == v.name })
+		})
+		if !bound {
+			return fmt.Errorf("capture variable $%s is not bound by an in option", v.name)
+		}
+	}
+	r.impPattern = &pat
+	return nil
+}
+
+// bindings returns the values of the capture variables bound by the including
+// in patterns matching pkgPath.
+func (r *rule) bindings(pkgPath string) map[string]string {
+	binds := make(map[string]string)
+	for _, pat := range r.in {
+		if pat.negate || len(pat.vars) == 0 {
+			continue
+		}
+		m := pat.re.FindStringSubmatch(pkgPath)
+		if m == nil {
+			continue
+		}
+		for i, v := range pat.vars {
+			if _, ok := binds[v.name]; !ok {
+				binds[v.name] = m[i+1]
+			}
+		}
+	}
+	return binds
+}
+
+// importSpecs returns the imports of f matched by the import path of the rule
+// for the importing package.
+func (r *rule) importSpecs(pkg *types.Package, f *ast.File) []*ast.ImportSpec {
+	if r.impPattern == nil {
+		return importSpec(f, r.imp, r.recursive)
+	}
+
+	binds := r.bindings(pkg.Path())
+	var specs []*ast.ImportSpec
+	for _, s := range f.Imports {
+		if r.impPattern.matchVars(importPath(s), binds) {
+			specs = append(specs, s)
+		}
+	}
+	return specs
+}
+
+// matchesPath returns true if the import path is matched by the rule.
+func (r *rule) matchesPath(impPath string) bool {
+	return impPath == r.imp || (r.recursive && strings.HasPrefix(impPath, r.imp+"/"))
+}
+
+// matchesSpec returns true if the kind of the import spec is matched by the
+// rule.
+func (r *rule) matchesSpec(spec *ast.ImportSpec) bool {
+	isBlank := spec.Name != nil && spec.Name.Name == "_"
+	if r.blank {
+		return isBlank
+	}
+	return !(r.noBlank && isBlank)
+}
+
+// pattern is a compiled package pattern, as used by the go command. The
+// "..." wildcard matches any string, including the empty string and strings
+// containing slashes. A pattern prefixed with ! negates the match.
+//
+// A $name capture variable matches a non-empty string without slashes. It's
+// bound to the matched value for the including in patterns. In import paths,
+// $name matches only the bound value, while !$name matches any other value.
+type pattern struct {
+	re     *regexp.Regexp
+	negate bool
+	vars   []patternVar
+}
+
+// patternVar is a capture variable of a pattern.
+type patternVar struct {
+	name   string
+	negate bool
+}
+
+var varRegexp = regexp.MustCompile(`(!?)\\\$(\w+)`)
+
+// compileVars replaces the capture variables of the quoted pattern re with
+// capturing groups.
+func compileVars(re string) (string, []patternVar) {
+	var vars []patternVar
+	re = varRegexp.ReplaceAllStringFunc(re, func(m string) string {
+		sub := varRegexp.FindStringSubmatch(m)
+		vars = append(vars, patternVar{name: sub[2], negate: sub[1] != ""})
+		return `([^/]+)`
+	})
+	return re, vars
+}
+
+// matchVars returns true if the pattern matches s and its capture variables
+// are consistent with binds. A variable without a binding matches any value.
+func (p *pattern) matchVars(s string, binds map[string]string) bool {
+	m := p.re.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	for i, v := range p.vars {
+		bound, ok := binds[v.name]
+		if ok && (m[i+1] == bound) == v.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// compilePattern compiles a package pattern, i.e: .../cmd/... or !net/http.
+func compilePattern(s string) (pattern, error) {
+	var p pattern
+	if strings.HasPrefix(s, "!") {
+		p.negate = true
+		s = s[1:]
+	}
+	if s == "" {
+		return pattern{}, fmt.Errorf("empty pattern")
+	}
+
+	re := regexp.QuoteMeta(s)
+	// Special case: foo/... matches foo too.
+	if strings.HasSuffix(re, `/\.\.\.`) {
+		re = strings.TrimSuffix(re, `/\.\.\.`) + `(/\.\.\.)?`
+	}
+	re = strings.ReplaceAll(re, `\.\.\.`, `.*`)
+	re, p.vars = compileVars(re)
+	if slices.ContainsFunc(p.vars, func(v patternVar) bool { return v.negate }) {
+		return pattern{}, fmt.Errorf("capture variables cannot be negated in pattern %q", s)
+	}
+
+	var err error
+	p.re, err = regexp.Compile(`^` + re + `$`)
+	return p, err
+}
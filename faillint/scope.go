@@ -0,0 +1,300 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// scopeKinds contains the kinds of enclosing declarations a scope can match.
+var scopeKinds = map[string]bool{
+	"test":       true, // func TestXxx in a _test.go file
+	"benchmark":  true, // func BenchmarkXxx in a _test.go file
+	"fuzz":       true, // func FuzzXxx in a _test.go file
+	"example":    true, // func ExampleXxx in a _test.go file
+	"init":       true, // func init
+	"main":       true, // func main of package main
+	"package":    true, // package level declarations, i.e: var initializers
+	"exported":   true, // exported functions and methods
+	"unexported": true, // unexported functions and methods
+}
+
+// scope restricts a rule to usages within certain enclosing declarations.
+// It's parsed from the scope and func options, i.e: [scope=test|benchmark]
+// or [func=!/^must/].
+type scope struct {
+	// kinds contains the kinds of which any should match the enclosing
+	// declaration.
+	kinds []string
+
+	// re is matched against the name of the enclosing function.
+	re *regexp.Regexp
+
+	// iface is implemented by the receiver type of the enclosing method.
+	iface *ifaceRef
+
+	// profile is the name of the profile the enclosing function or file is
+	// annotated with.
+	profile string
+
+	// negate is true if the scope should not match the enclosing
+	// declaration.
+	negate bool
+}
+
+// parseScope parses the value of a scope option.
+func parseScope(value string) (scope, error) {
+	var s scope
+	if strings.HasPrefix(value, "!") {
+		s.negate = true
+		value = value[1:]
+	}
+	for _, kind := range strings.Split(value, "|") {
+		if !scopeKinds[kind] {
+			return scope{}, fmt.Errorf("unknown scope %q", kind)
+		}
+		s.kinds = append(s.kinds, kind)
+	}
+	return s, nil
+}
+
+// parseFuncScope parses the value of a func option.
+func parseFuncScope(value string) (scope, error) {
+	var s scope
+	if strings.HasPrefix(value, "!") {
+		s.negate = true
+		value = value[1:]
+	}
+	re, err := parseRegexp(value)
+	if err != nil {
+		return scope{}, err
+	}
+	s.re = re
+	return s, nil
+}
+
+// parseImplementsScope parses the value of an implements option, i.e:
+// net/http.Handler.
+func parseImplementsScope(value string) (scope, error) {
+	var s scope
+	if strings.HasPrefix(value, "!") {
+		s.negate = true
+		value = value[1:]
+	}
+	dot := strings.LastIndexByte(value, '.')
+	if dot <= strings.LastIndexByte(value, '/') || dot == len(value)-1 {
+		return scope{}, fmt.Errorf("interface %q must be in the form of import/path.Name", value)
+	}
+	s.iface = &ifaceRef{path: value[:dot], name: value[dot+1:]}
+	return s, nil
+}
+
+// profileKey is the directive activating profiles for the annotated function
+// or file, i.e: //faillint:profile hotpath
+const profileKey = "//faillint:profile"
+
+// parseProfileScope parses the value of a profile option.
+func parseProfileScope(value string) (scope, error) {
+	var s scope
+	if strings.HasPrefix(value, "!") {
+		s.negate = true
+		value = value[1:]
+	}
+	if !profileRegexp.MatchString(value) {
+		return scope{}, fmt.Errorf("invalid profile name %q", value)
+	}
+	s.profile = value
+	return s, nil
+}
+
+var profileRegexp = regexp.MustCompile(`^[\w-]+$`)
+
+// hasProfile returns true if any of the comments has a profile directive for
+// the given profile.
+func hasProfile(comments []*ast.CommentGroup, profile string) bool {
+	for _, cg := range comments {
+		if cg == nil {
+			continue
+		}
+		for _, c := range cg.List {
+			args, ok := strings.CutPrefix(c.Text, profileKey)
+			if ok && args != "" && (args[0] == ' ' || args[0] == '\t') && slices.Contains(strings.Fields(args), profile) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchProfile returns true if pos is within the body of a function annotated
+// with the profile, or within a file annotated with it before the package
+// clause.
+func matchProfile(file *ast.File, fn *ast.FuncDecl, pos token.Pos, profile string) bool {
+	var header []*ast.CommentGroup
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+		header = append(header, cg)
+	}
+	if hasProfile(header, profile) {
+		return true
+	}
+	return fn != nil && fn.Body != nil && fn.Body.Pos() <= pos && pos < fn.Body.End() && hasProfile([]*ast.CommentGroup{fn.Doc}, profile)
+}
+
+// ifaceRef references a named interface by its package path and name. It's
+// resolved lazily from the dependencies of the analyzed package.
+type ifaceRef struct {
+	path, name string
+
+	resolved bool
+	iface    *types.Interface
+}
+
+// lookup returns the referenced interface, or nil if the analyzed package
+// doesn't depend on its package.
+func (r *ifaceRef) lookup(pkg *types.Package) *types.Interface {
+	if r.resolved {
+		return r.iface
+	}
+	r.resolved = true
+
+	dep := findPackage(pkg, r.path)
+	if dep == nil {
+		return nil
+	}
+	obj, ok := dep.Scope().Lookup(r.name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	r.iface, _ = obj.Type().Underlying().(*types.Interface)
+	return r.iface
+}
+
+// findPackage returns the package with the given path among pkg and its
+// transitive dependencies, or nil if there is none.
+func findPackage(pkg *types.Package, path string) *types.Package {
+	seen := map[*types.Package]bool{}
+	queue := []*types.Package{pkg}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if p.Path() == path {
+			return p
+		}
+		for _, imp := range p.Imports() {
+			if !seen[imp] {
+				seen[imp] = true
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return nil
+}
+
+// implements returns true if the receiver type of fn, or a pointer to it,
+// implements iface.
+func implements(pass *analysis.Pass, fn *ast.FuncDecl, iface *types.Interface) bool {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return false
+	}
+	recv := pass.TypesInfo.TypeOf(fn.Recv.List[0].Type)
+	if recv == nil {
+		return false
+	}
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	return types.Implements(recv, iface) || types.Implements(types.NewPointer(recv), iface)
+}
+
+// match returns true if the scope matches the declaration enclosing pos.
+func (s scope) match(pass *analysis.Pass, file *ast.File, pos token.Pos) bool {
+	fn := enclosingFunc(file, pos)
+
+	matched := false
+	if s.re != nil {
+		matched = fn != nil && s.re.MatchString(fn.Name.Name)
+	}
+	if s.iface != nil {
+		iface := s.iface.lookup(pass.Pkg)
+		matched = fn != nil && iface != nil && implements(pass, fn, iface)
+	}
+	if s.profile != "" {
+		matched = matchProfile(file, fn, pos, s.profile)
+	}
+	for _, kind := range s.kinds {
+		if matchScopeKind(pass, file, fn, kind) {
+			matched = true
+			break
+		}
+	}
+	return matched != s.negate
+}
+
+// matchScopeKind returns true if fn, which is nil for package level
+// declarations, is of the given kind.
+func matchScopeKind(pass *analysis.Pass, file *ast.File, fn *ast.FuncDecl, kind string) bool {
+	if kind == "package" {
+		return fn == nil
+	}
+	if fn == nil {
+		return false
+	}
+
+	name := fn.Name.Name
+	isTestFile := strings.HasSuffix(pass.Fset.File(file.Package).Name(), "_test.go")
+	switch kind {
+	case "test":
+		return isTestFile && fn.Recv == nil && isTestFunc(name, "Test")
+	case "benchmark":
+		return isTestFile && fn.Recv == nil && isTestFunc(name, "Benchmark")
+	case "fuzz":
+		return isTestFile && fn.Recv == nil && isTestFunc(name, "Fuzz")
+	case "example":
+		return isTestFile && fn.Recv == nil && strings.HasPrefix(name, "Example")
+	case "init":
+		return fn.Recv == nil && name == "init"
+	case "main":
+		return fn.Recv == nil && name == "main" && file.Name.Name == "main"
+	case "exported":
+		return ast.IsExported(name)
+	case "unexported":
+		return !ast.IsExported(name)
+	}
+	return false
+}
+
+// isTestFunc reports whether name looks like a test, benchmark or fuzz
+// function with the given prefix. It is a Test (say) if there is a character
+// after Test that is not a lower-case letter, as defined by the go command.
+func isTestFunc(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// enclosingFunc returns the top level function declaration enclosing pos, or
+// nil if pos is part of a package level declaration.
+func enclosingFunc(file *ast.File, pos token.Pos) *ast.FuncDecl {
+	for _, d := range file.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Pos() <= pos && pos < fn.End() {
+			return fn
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package a
+
+import (
+	. "errors"
+	e "errors"
+	"fmt"
+)
+
+type wrapped struct{ New error }
+
+func foo() error {
+	err := New("bar!") // want `declaration "New" from package "errors" shouldn't be used`
+	if Is(err, ErrUnsupported) {
+		return Unwrap(err)
+	}
+
+	//lint:ignore faillint tolerate this errors.New usage
+	_ = New("baz!")
+
+	_ = e.New("qux!") // want `declaration "New" from package "errors" shouldn't be used`
+
+	w := wrapped{New: err}
+	_ = w.New
+
+	New := fmt.Errorf
+	return New("qux!")
+}
@@ -0,0 +1,24 @@
+package alias
+
+import (
+	"encoding/json"    // want `package "encoding/json" should be imported with alias "stdjson"`
+	errs "errors"      // want `package "errors" shouldn't be imported with an alias`
+	nethttp "net/http" // want `alias "nethttp" of package "net/http" shouldn't be used, suggested: "http"`
+	str "strings"      // want `package "strings" should be imported with alias "stdstrings"`
+	"time"
+	tm "time" // want `package "time" shouldn't be imported with an alias`
+)
+
+func foo() error {
+	_, _ = json.Marshal(nil)
+	_ = nethttp.DefaultClient
+	_ = str.ToUpper("foo")
+	_ = time.Now()
+	_ = tm.Now()
+
+	var err error = errs.New("foo")
+	if errs.Is(err, err) {
+		return errs.Unwrap(err)
+	}
+	return err
+}
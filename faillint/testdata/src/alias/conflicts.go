@@ -0,0 +1,13 @@
+package alias
+
+import (
+	"fmt"
+	u "net/url" // want `package "net/url" should be imported with alias "path"`
+	"path"
+	fp "path/filepath" // want `package "path/filepath" shouldn't be imported with an alias`
+	strconv "strconv"  // want `package "strconv" shouldn't be imported with an alias`
+)
+
+func bar(filepath string) string {
+	return fmt.Sprint(fp.Base(filepath), u.PathEscape(path.Base(filepath)), strconv.Itoa(1))
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"database/sql" // want `package "database/sql" shouldn't be imported`
+	"net/http"     // want `package "net/http" shouldn't be imported`
+	_ "net/http/pprof"
+
+	_ "errors" // want `package "errors" shouldn't be imported with a blank identifier`
+)
+
+func main() {
+	_, _ = sql.Open("postgres", "")
+	_ = http.ListenAndServe(":8080", nil)
+}
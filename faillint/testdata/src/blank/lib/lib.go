@@ -0,0 +1,11 @@
+package lib
+
+import (
+	_ "errors" // want `package "errors" shouldn't be imported with a blank identifier`
+	"net/http"
+	_ "net/http/pprof" // want `package "net/http/pprof" shouldn't be imported with a blank identifier, suggested: "net/http/pprof.{Handler}"`
+
+	_ "database/sql"
+)
+
+var _ = http.DefaultServeMux
@@ -0,0 +1,33 @@
+package builtins
+
+import (
+	"errors"
+	"unsafe"
+)
+
+func foo(xs []int) {
+	println("foo")  // want `builtin function "println" shouldn't be used, suggested: "log.Println"`
+	print("foo")    // want `builtin function "print" shouldn't be used, suggested: "log.Println"`
+	_ = len(xs)     // ok
+	xs = append(xs) // ok
+	_ = unsafe.Sizeof(xs)
+
+	if len(xs) == 0 {
+		panic(errors.New("empty")) // want `builtin function "panic" shouldn't be used, suggested: "log.Println"`
+	}
+
+	//lint:ignore faillint tolerated debug output
+	println("bar")
+}
+
+type logger struct{}
+
+func (logger) print(s string) {}
+
+func bar() {
+	print := func(s string) {}
+	print("foo")
+
+	var l logger
+	l.print("foo")
+}
@@ -0,0 +1,46 @@
+package constructs
+
+import "io"
+
+var (
+	counter int       // want `package level variable shouldn't be used`
+	_       io.Reader = reader{}
+)
+
+//lint:ignore faillint tolerated sentinel error
+var errTolerated = io.EOF
+
+const limit = 10
+
+type reader struct{}
+
+func (reader) Read(p []byte) (int, error) { return 0, errTolerated }
+
+func init() { // want `init function shouldn't be used`
+	counter = limit
+}
+
+func foo(ch chan int) {
+	go func() { ch <- 1 }() // want `go statement shouldn't be used, suggested: "errgroup.Group.Go"`
+
+	select { // want `select statement shouldn't be used`
+	case <-ch:
+	default:
+	}
+
+	defer func() {
+		_ = recover() // want `recover call shouldn't be used`
+	}()
+
+	i := 0
+loop:
+	i++
+	if i < limit {
+		goto loop // want `goto statement shouldn't be used`
+	}
+}
+
+func recoverLocal() {
+	recover := func() any { return nil }
+	_ = recover()
+}
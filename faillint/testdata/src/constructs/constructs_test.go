@@ -0,0 +1,9 @@
+package constructs
+
+import "testing"
+
+func TestFoo(t *testing.T) {
+	ch := make(chan int)
+	go func() { ch <- 1 }()
+	<-ch
+}
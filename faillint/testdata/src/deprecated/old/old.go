@@ -0,0 +1,65 @@
+package old
+
+// Foo does foo.
+//
+// Deprecated: Use Bar instead.
+func Foo() {
+	// Usages within the same package are not reported.
+	_ = Limit
+}
+
+// Bar does bar.
+func Bar() {}
+
+// Deprecated constants.
+//
+// Deprecated: Limits are not enforced
+// anymore.
+const (
+	Limit    = 10
+	MaxLimit = 20
+)
+
+// T is a type.
+type T struct {
+	// Name is the name.
+	//
+	// Deprecated: Use FullName instead.
+	Name string
+
+	FullName string
+}
+
+// Method does something.
+//
+// Deprecated: Use NewMethod instead.
+func (T) Method() {}
+
+// NewMethod does something.
+func (T) NewMethod() {}
+
+// List is a generic list.
+type List[E any] struct {
+	// Items are the items.
+	//
+	// Deprecated: Use All instead.
+	Items []E
+}
+
+// OldMethod does something.
+//
+// Deprecated: Use NewMethod instead.
+func (l *List[E]) OldMethod() {}
+
+// NewMethod does something.
+func (l *List[E]) NewMethod() {}
+
+// Map maps xs.
+//
+// Deprecated: Use slices instead.
+func Map[E any](xs []E) []E { return xs }
+
+// Old is an old type.
+//
+// Deprecated: Use T instead.
+type Old = T
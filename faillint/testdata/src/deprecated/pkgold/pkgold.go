@@ -0,0 +1,8 @@
+// Package pkgold does old things.
+//
+// Deprecated: Use package
+// deprecated/old instead.
+package pkgold
+
+// Foo does foo.
+func Foo() {}
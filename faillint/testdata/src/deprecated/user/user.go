@@ -0,0 +1,46 @@
+package user
+
+import (
+	"io/ioutil" // want `package "io/ioutil" is deprecated, suggested: "As of Go 1.16, .*"`
+	"strings"
+
+	"deprecated/old"
+	"deprecated/pkgold" // want `package "deprecated/pkgold" is deprecated, suggested: "Use package deprecated/old instead."`
+	"modfake/fake"
+	"modold/pkg" // want `module "modold" of package "modold/pkg" is deprecated, suggested: "Use modnew instead."`
+)
+
+func foo() {
+	old.Foo() // want `declaration "Foo" from package "deprecated/old" is deprecated, suggested: "Use Bar instead."`
+	old.Bar()
+
+	_ = old.Limit    // want `declaration "Limit" from package "deprecated/old" is deprecated, suggested: "Limits are not enforced anymore."`
+	_ = old.MaxLimit // want `declaration "MaxLimit" from package "deprecated/old" is deprecated`
+
+	var t old.T
+	t.Method() // want `declaration "T.Method" from package "deprecated/old" is deprecated, suggested: "Use NewMethod instead."`
+	t.NewMethod()
+	_ = t.Name // want `declaration "Name" from package "deprecated/old" is deprecated, suggested: "Use FullName instead."`
+	_ = old.T{
+		Name: "foo", // want `declaration "Name" from package "deprecated/old" is deprecated`
+	}
+
+	var l old.List[int]
+	l.OldMethod() // want `declaration "List.OldMethod" from package "deprecated/old" is deprecated, suggested: "Use NewMethod instead."`
+	l.NewMethod()
+	_ = l.Items              // want `declaration "Items" from package "deprecated/old" is deprecated, suggested: "Use All instead."`
+	_ = old.Map([]int{1})    // want `declaration "Map" from package "deprecated/old" is deprecated, suggested: "Use slices instead."`
+	_ = old.Map[string](nil) // want `declaration "Map" from package "deprecated/old" is deprecated`
+
+	var _ old.Old // want `declaration "Old" from package "deprecated/old" is deprecated, suggested: "Use T instead."`
+
+	_, _ = ioutil.ReadAll(nil) // want `declaration "ReadAll" from package "io/ioutil" is deprecated, suggested: "As of Go 1.16, .*"`
+	_ = strings.Title("foo")
+
+	pkgold.Foo()
+	pkg.Foo()
+	fake.Foo()
+
+	//lint:ignore faillint tolerated until the next release
+	old.Foo()
+}
@@ -0,0 +1,8 @@
+package main
+
+// #cgo CFLAGS: -DNDEBUG=1
+/* #cgo LDFLAGS: -lm
+#include <math.h>
+// want `directive "#cgo LDFLAGS" shouldn't be used`
+*/
+import "C"
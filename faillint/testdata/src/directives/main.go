@@ -0,0 +1,31 @@
+/* want `directive "//go:debug" shouldn't be used` */ //go:debug panicnil=1
+//go:debug http2client=0
+
+package main
+
+import (
+	_ "embed"
+	_ "unsafe"
+)
+
+/* want `directive "//go:linkname" shouldn't be used, suggested: "time.Now"` */ //go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+// The directive is fine here.
+//
+//lint:ignore faillint needed for the benchmark
+//go:linkname fastrand runtime.fastrand
+func fastrand() uint32
+
+//go:noinline
+func main() {}
+
+//go:embed main.go
+var src string
+
+/* want `directive "//go:generate go" shouldn't be used` */ //go:generate go run github.com/example/gen@latest -out gen.go
+//go:generate go run github.com/example/gen@v1.2.3 -out gen.go
+/* want `directive "//go:generate mockgen" shouldn't be used, suggested: "go.uber.org/mock/mockgen"` */ //go:generate mockgen -source=main.go
+//go:generate stringer -type "My Type"
+/* want `directive "//go:generate chmod" shouldn't be used` */ //go:generate chmod 0x1ff gen.sh
+//go:generate chmod 0644 gen.sh
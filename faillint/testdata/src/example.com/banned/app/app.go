@@ -0,0 +1,13 @@
+package app
+
+import "example.com/banned/client"
+
+func App() {
+	c := client.NewClient() // want `declaration "NewClient" from package "example.com/banned/client" shouldn't be used, suggested: "use NewClientV2 instead"`
+	_ = c.Addr              // want `declaration "Addr" from package "example.com/banned/client" shouldn't be used`
+	_ = client.Fake()       // want `declaration "Fake" from package "example.com/banned/client" should only be used in tests`
+	_ = client.NewClientV2()
+
+	//lint:ignore faillint migrating
+	_ = client.NewClient()
+}
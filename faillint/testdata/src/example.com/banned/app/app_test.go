@@ -0,0 +1,11 @@
+package app
+
+import (
+	"testing"
+
+	"example.com/banned/client"
+)
+
+func TestApp(t *testing.T) {
+	_ = client.Fake()
+}
@@ -0,0 +1,24 @@
+package client
+
+type Client struct {
+	// Addr is the address of the server.
+	//
+	//faillint:banned
+	Addr string
+}
+
+// NewClient returns a new client.
+//
+//faillint:banned use NewClientV2 instead
+func NewClient() *Client { return newClient() }
+
+// NewClientV2 returns a new client.
+func NewClientV2() *Client { return newClient() }
+
+// Fake returns a fake client.
+//
+//faillint:testonly
+func Fake() *Client { return newClient() }
+
+//faillint:banned
+func newClient() *Client { return &Client{} }
@@ -0,0 +1,6 @@
+// Package hotpath is performance critical.
+package hotpath
+
+//faillint:forbid fmt.{Print*,Sprintf} no formatting in the hot path
+//faillint:forbid reflect
+//faillint:forbid errors=example.com/errs avoid allocations
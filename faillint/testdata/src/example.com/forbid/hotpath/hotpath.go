@@ -0,0 +1,16 @@
+package hotpath
+
+import (
+	"errors" // want `package "errors" shouldn't be imported, suggested: "example.com/errs: avoid allocations"`
+	"fmt"
+	"reflect" // want `package "reflect" shouldn't be imported`
+	"strconv"
+)
+
+func Run(n int) string {
+	fmt.Println(n) // want `declaration "Println" from package "fmt" shouldn't be used, suggested: "no formatting in the hot path"`
+	_ = errors.New("")
+	_ = reflect.TypeOf(n)
+	_ = fmt.Errorf("x")
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,4 @@
+package hotpath
+
+/* want `invalid forbid directive: invalid rule "fmt.{Print\*"` */ //faillint:forbid fmt.{Print*
+/* want `invalid forbid directive: invalid rule "fmt.{Sprintf}\[scope=test"` */ //faillint:forbid fmt.{Sprintf}[scope=test
@@ -0,0 +1,11 @@
+package other
+
+import (
+	"fmt"
+
+	"example.com/forbid/hotpath"
+)
+
+func Other() {
+	fmt.Println(hotpath.Run(1))
+}
@@ -0,0 +1,17 @@
+package app
+
+import (
+	"example.com/names/helpers" // want `package "example.com/names/helpers" with name "helperfuncs" shouldn't be imported`
+	"example.com/names/other"   // want `package "example.com/names/other" with name "common" shouldn't be imported, suggested: "example.com/names/shared"`
+	"example.com/names/util"    // want `package "example.com/names/util" with name "util" shouldn't be imported`
+
+	//lint:ignore faillint it's fine
+	"strings"
+)
+
+func App() {
+	util.Foo()
+	helperfuncs.Bar()
+	common.Baz()
+	_ = strings.ToUpper
+}
@@ -0,0 +1,3 @@
+package helperfuncs
+
+func Bar() {}
@@ -0,0 +1,4 @@
+package buildinfo
+
+// Version is the version.
+var Version = "dev"
@@ -0,0 +1,7 @@
+package main // want `package "example.com/required/cmd/app" should blank import "time/tzdata"` `package "example.com/required/cmd/app" should import "example.com/required/buildinfo", suggested: "example.com/required/buildinfo.{Version}"`
+
+import "fmt"
+
+func main() {
+	fmt.Println("app")
+}
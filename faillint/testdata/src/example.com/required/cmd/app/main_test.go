@@ -0,0 +1,5 @@
+package main_test
+
+import "testing"
+
+func TestMain(t *testing.T) {}
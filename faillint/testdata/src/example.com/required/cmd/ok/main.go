@@ -0,0 +1,11 @@
+package main
+
+import (
+	_ "time/tzdata"
+
+	"example.com/required/buildinfo"
+)
+
+func main() {
+	println(buildinfo.Version)
+}
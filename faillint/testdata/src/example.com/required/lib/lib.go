@@ -0,0 +1,4 @@
+package lib
+
+// Foo does foo.
+func Foo() {}
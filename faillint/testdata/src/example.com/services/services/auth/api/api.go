@@ -0,0 +1,12 @@
+package api
+
+import (
+	"example.com/services/pkg/log"
+	"example.com/services/services/auth/store"
+	billing "example.com/services/services/billing/store" // want `package "example.com/services/services/billing/store" shouldn't be imported`
+)
+
+func Handle() {
+	log.Print(store.Get())
+	log.Print(billing.Get())
+}
@@ -0,0 +1,11 @@
+package api
+
+import (
+	//lint:ignore faillint migrating away
+	auth "example.com/services/services/auth/store"
+	"example.com/services/services/billing/store"
+)
+
+func Handle() string {
+	return auth.Get() + store.Get()
+}
@@ -0,0 +1,6 @@
+package client
+
+import "net/http"
+
+// Client is a client.
+var Client = http.DefaultClient
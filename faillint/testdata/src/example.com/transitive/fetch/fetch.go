@@ -0,0 +1,12 @@
+package fetch
+
+import "net/http"
+
+// Get fetches the URL.
+func Get(url string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
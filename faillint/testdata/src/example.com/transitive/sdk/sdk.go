@@ -0,0 +1,18 @@
+package sdk // want package:"imports: net/http strings example.com/transitive/client example.com/transitive/fetch example.com/transitive/wrap"
+
+import (
+	"net/http" // want `package "net/http" shouldn't be imported`
+	"strings"
+
+	"example.com/transitive/client" // want `package "net/http" shouldn't be imported, imported through: example.com/transitive/client -> net/http`
+	"example.com/transitive/fetch"  // want `package "net/http" shouldn't be imported, imported through: example.com/transitive/fetch -> net/http`
+	"example.com/transitive/wrap"   // want `package "net/http" shouldn't be imported, imported through: example.com/transitive/wrap -> example.com/transitive/client -> net/http`
+)
+
+var (
+	_ = http.DefaultClient
+	_ = strings.ToUpper
+	_ = client.Client
+	_ = wrap.Client
+	_ = fetch.Get
+)
@@ -0,0 +1,8 @@
+package sdk
+
+import (
+	//lint:ignore faillint the client is tolerated here
+	"example.com/transitive/client"
+)
+
+var _ = client.Client
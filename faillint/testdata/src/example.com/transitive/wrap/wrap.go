@@ -0,0 +1,6 @@
+package wrap
+
+import "example.com/transitive/client"
+
+// Client is a wrapped client.
+var Client = client.Client
@@ -0,0 +1,4 @@
+// Package secrets provides access to the secrets of the platform.
+//
+//faillint:visibility ./services/auth/... ./cmd/...
+package secrets
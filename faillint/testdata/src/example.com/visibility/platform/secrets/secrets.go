@@ -0,0 +1,3 @@
+package secrets
+
+func Get(name string) string { return name }
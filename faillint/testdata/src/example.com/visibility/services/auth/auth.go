@@ -0,0 +1,7 @@
+package auth
+
+import "example.com/visibility/platform/secrets"
+
+func Token() string {
+	return secrets.Get("token")
+}
@@ -0,0 +1,9 @@
+package billing
+
+import (
+	"example.com/visibility/platform/secrets" // want `package "example.com/visibility/platform/secrets" isn't visible to package "example.com/visibility/services/billing", visible to: example.com/visibility/services/auth/..., example.com/visibility/cmd/...`
+)
+
+func Key() string {
+	return secrets.Get("key")
+}
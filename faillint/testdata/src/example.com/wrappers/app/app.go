@@ -0,0 +1,30 @@
+package app
+
+import (
+	"example.com/wrappers/util"
+)
+
+func foo() { // want foo:"wraps: app.foo -> util.Die -> os.Exit"
+	util.Die()         // want `declaration "Exit" from package "os" shouldn't be used, called through: util.Die -> os.Exit`
+	util.Fatal("oops") // want `declaration "Exit" from package "os" shouldn't be used, called through: util.Fatal -> util.die -> util.Die -> os.Exit`
+
+	var e util.Exiter
+	e.Exit() // want `declaration "Exit" from package "os" shouldn't be used, called through: util.Exiter.Exit -> os.Exit`
+
+	var b util.Box[int]
+	b.Exit()                   // want `declaration "Exit" from package "os" shouldn't be used, called through: util.Box.Exit -> os.Exit`
+	_ = util.Must[int](1, nil) // want `declaration "Exit" from package "os" shouldn't be used, called through: util.Must -> os.Exit`
+
+	util.Print("ok")
+	util.Tolerated()
+	util.Quit()
+	_ = util.Format(1) // want `declaration "Sprint" from package "fmt" shouldn't be used, called through: util.Format -> fmt.Sprint`
+
+	//lint:ignore faillint exiting is tolerated here
+	util.Die()
+
+	f := util.Die
+	f()
+
+	util.Kill() // want `declaration "Kill" from package "example.com/wrappers/util" shouldn't be used`
+}
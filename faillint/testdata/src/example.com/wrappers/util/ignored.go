@@ -0,0 +1,10 @@
+//lint:file-ignore faillint quitting is tolerated in this file
+
+package util
+
+import "os"
+
+// Quit exits the program.
+func Quit() {
+	os.Exit(0)
+}
@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"os"
+)
+
+// Die exits the program.
+func Die() {
+	os.Exit(1)
+}
+
+// Fatal prints the message and exits the program.
+func Fatal(msg string) {
+	fmt.Println(msg)
+	die()
+}
+
+func die() {
+	Die()
+}
+
+// Exiter exits.
+type Exiter struct{}
+
+// Exit exits the program.
+func (*Exiter) Exit() {
+	os.Exit(1)
+}
+
+// Box is a generic box.
+type Box[T any] struct{ V T }
+
+// Exit exits the program.
+func (Box[T]) Exit() {
+	os.Exit(1)
+}
+
+// Must returns v, or exits the program if err is not nil.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		os.Exit(1)
+	}
+	return v
+}
+
+// Print prints the message.
+func Print(msg string) {
+	fmt.Println(msg)
+}
+
+// Kill kills the program.
+func Kill() {
+	os.Exit(1)
+}
+
+// Tolerated exits the program.
+func Tolerated() {
+	//lint:ignore faillint exiting is tolerated here
+	os.Exit(1)
+}
+
+// Format formats the value.
+func Format(v any) string {
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,18 @@
+package g
+
+import (
+	"fmt"
+	"os"
+)
+
+func foo() {
+	fmt.Print("foo")        // want `declaration "Print" from package "fmt" shouldn't be used`
+	fmt.Printf("%s", "foo") // want `declaration "Printf" from package "fmt" shouldn't be used`
+	fmt.Println("foo")      // want `declaration "Println" from package "fmt" shouldn't be used`
+	_ = fmt.Sprint("foo")
+	_ = fmt.Sprintf("%s", "foo") // want `declaration "Sprintf" from package "fmt" shouldn't be used`
+	_ = fmt.Sprintln("foo")      // want `declaration "Sprintln" from package "fmt" shouldn't be used`
+	fmt.Fprint(os.Stdout, "foo")
+
+	os.Exit(1) // want `declaration "Exit" from package "os" shouldn't be used, suggested: "github.com/foo/bar.{Exit}"`
+}
@@ -0,0 +1,15 @@
+package ignoreenclosing
+
+import (
+	"errors"
+)
+
+//lint:ignore faillint the whole function is ignored
+func Foo() error {
+	_ = errors.New("foo")
+	return errors.New("foo")
+}
+
+func Bar() error {
+	return errors.New("bar") // want `declaration "New" from package "errors" shouldn't be used`
+}
@@ -0,0 +1,55 @@
+package ignorescope
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+//lint:ignore faillint io.Reader is fine in the signature
+func Foo(r io.Reader) error {
+	_ = errors.New("foo") // want `declaration "New" from package "errors" shouldn't be used`
+	return nil
+}
+
+func Bar() error {
+	//lint:ignore faillint the whole statement is ignored
+	_ = fmt.Errorf("bar: %w",
+		errors.New("bar"))
+	_ = errors.New("bar") // want `declaration "New" from package "errors" shouldn't be used`
+
+	//lint:ignore faillint the condition is ignored
+	if err := errors.New("bar"); err != nil {
+		return errors.New("bar") // want `declaration "New" from package "errors" shouldn't be used`
+	}
+
+	//lint:ignore faillint the call is ignored, but not the body of the literal
+	_ = fmt.Errorf("bar: %w", func() error {
+		return errors.New("bar") // want `declaration "New" from package "errors" shouldn't be used`
+	}())
+
+	return errors.New("bar") //lint:ignore faillint only this line is ignored
+}
+
+func Baz() error {
+	//lint:ignore-start faillint:errors generated error values
+	_ = errors.New("baz")
+	_ = errors.New("baz")
+	_ = fmt.Errorf("baz") // want `declaration "Errorf" from package "fmt" shouldn't be used`
+	//lint:ignore-end faillint
+
+	//lint:ignore-start faillint nothing to suppress // want `unused faillint directive`
+	//lint:ignore-end faillint
+
+	//lint:ignore-end faillint // want `faillint:ignore-end directive without faillint:ignore-start directive`
+
+	//lint:ignore-end faillint:errors // want `targets are not supported on faillint:ignore-end directive`
+
+	//lint:ignore-start faillint:errors nested ranges
+	//lint:ignore-start faillint nested range // want `faillint:ignore-start directive within the range of another one`
+	_ = errors.New("baz")
+	//lint:ignore-end faillint
+
+	//lint:ignore-start faillint not ended // want `faillint:ignore-start directive without faillint:ignore-end directive`
+	return errors.New("baz") // want `declaration "New" from package "errors" shouldn't be used`
+}
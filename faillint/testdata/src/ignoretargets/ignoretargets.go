@@ -0,0 +1,20 @@
+package ignoretargets
+
+import (
+	"errors"
+	"fmt"
+)
+
+func foo(err error) error {
+	//lint:ignore faillint:fmt.Errorf wrapping is fine here
+	_ = errors.New(fmt.Errorf("bar: %w", err).Error()) // want `declaration "New" from package "errors" shouldn't be used`
+
+	//lint:ignore faillint:errors,fmt.Errorf both are fine here
+	_ = errors.New(fmt.Errorf("bar: %w", err).Error())
+
+	//lint:ignore faillint:no-sprintf formatting is fine here
+	_ = errors.New(fmt.Sprintf("bar: %v", err)) // want `declaration "New" from package "errors" shouldn't be used`
+
+	//lint:ignore faillint:fmt.Sprintf not what is used here // want `unused faillint directive`
+	return fmt.Errorf("bar: %w", err) // want `declaration "Errorf" from package "fmt" shouldn't be used`
+}
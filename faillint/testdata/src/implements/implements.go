@@ -0,0 +1,39 @@
+package implements
+
+import (
+	"context"
+	"net/http"
+)
+
+type handler struct{}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = context.Background() // want `declaration "Background" from package "context" shouldn't be used, suggested: "r.Context"`
+	h.serve(context.TODO())  // want `declaration "TODO" from package "context" shouldn't be used, suggested: "r.Context"`
+}
+
+func (h *handler) serve(ctx context.Context) {
+	_ = context.Background()
+}
+
+type notHandler struct{}
+
+func (notHandler) ServeHTTP(w http.ResponseWriter) {
+	_ = context.Background()
+}
+
+// GreeterServer is the server API for the Greeter service.
+type GreeterServer interface {
+	SayHello(context.Context, string) (string, error)
+}
+
+type greeter struct{}
+
+func (greeter) SayHello(ctx context.Context, name string) (string, error) {
+	_ = context.TODO() // want `declaration "TODO" from package "context" shouldn't be used`
+	return name, nil
+}
+
+func helper() {
+	_ = context.Background()
+}
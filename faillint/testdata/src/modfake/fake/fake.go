@@ -0,0 +1,4 @@
+package fake
+
+// Foo does foo.
+func Foo() {}
@@ -0,0 +1,4 @@
+// Deprecated: Use modnew instead.
+module example.com/modfake
+
+go 1.22
@@ -0,0 +1,4 @@
+// Deprecated: Use modnew instead.
+module modold
+
+go 1.22
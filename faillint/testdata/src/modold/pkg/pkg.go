@@ -0,0 +1,4 @@
+package pkg
+
+// Foo does foo.
+func Foo() {}
@@ -0,0 +1,10 @@
+//faillint:profile alloc
+
+package profiles
+
+import "strings"
+
+func Join(s []string) string {
+	_ = strings.Split("", ",")  // want `declaration "Split" from package "strings" shouldn't be used`
+	return strings.Join(s, ",") // want `declaration "Join" from package "strings" shouldn't be used`
+}
@@ -0,0 +1,25 @@
+package profiles
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Hot is performance critical.
+//
+//faillint:profile hotpath
+func Hot(v any) string {
+	_ = reflect.TypeOf(v)       // want `declaration "TypeOf" from package "reflect" shouldn't be used`
+	_ = time.Now()              // want `declaration "Now" from package "time" shouldn't be used, suggested: "time.{Since}"`
+	return fmt.Sprintf("%v", v) // want `declaration "Sprintf" from package "fmt" shouldn't be used`
+}
+
+//faillint:profile hotpath
+func Signature(t reflect.Type) {}
+
+func Cold(v any) string {
+	_ = reflect.TypeOf(v)
+	_ = time.Now()
+	return fmt.Sprintf("%v", v)
+}
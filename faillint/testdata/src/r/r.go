@@ -0,0 +1,29 @@
+package r
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const secretKey = "AWS_SECRET_ACCESS_KEY"
+
+func foo(pattern string) {
+	_ = os.Getenv("AWS_SECRET_ACCESS_KEY") // want `declaration "Getenv" from package "os" shouldn't be used`
+	_ = os.Getenv(secretKey)               // want `declaration "Getenv" from package "os" shouldn't be used`
+	_ = os.Getenv("AWS_SESSION_TOKEN")     // want `declaration "Getenv" from package "os" shouldn't be used`
+	_ = os.Getenv("HOME")
+	_ = os.Getenv(pattern)
+
+	_ = os.MkdirAll("foo", 0777) // want `declaration "MkdirAll" from package "os" shouldn't be used`
+	_ = os.MkdirAll("foo", 0755)
+
+	_ = regexp.MustCompile(pattern) // want `declaration "MustCompile" from package "regexp" shouldn't be used`
+	_ = regexp.MustCompile("^foo$")
+
+	_ = filepath.Join("foo", "..", pattern) // want `declaration "Join" from package "path/filepath" shouldn't be used`
+	_ = filepath.Join("foo", pattern)
+
+	getenv := os.Getenv
+	_ = getenv("AWS_SECRET_ACCESS_KEY")
+}
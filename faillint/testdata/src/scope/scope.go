@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+)
+
+var (
+	validName = regexp.MustCompile(`^[a-z]+$`)
+	client    = http.Client{}
+)
+
+func init() {
+	_, _ = http.Get("https://example.com") // want `declaration "Get" from package "net/http" shouldn't be used`
+	_ = regexp.MustCompile(`^[a-z]+$`)     // want `declaration "MustCompile" from package "regexp" shouldn't be used`
+}
+
+func main() {
+	if !validName.MatchString(os.Args[0]) {
+		log.Fatal("invalid name")
+	}
+	_ = client
+}
+
+func helper() {
+	log.Fatal("failed") // want `declaration "Fatal" from package "log" shouldn't be used`
+}
+
+func Exported() string {
+	return os.Getenv("HOME") // want `declaration "Getenv" from package "os" shouldn't be used`
+}
+
+func NewExported() string {
+	return os.Getenv("HOME")
+}
+
+func unexported() string {
+	return os.Getenv("HOME")
+}
+
+type T struct{}
+
+func (T) Method() string {
+	return os.Getenv("HOME") // want `declaration "Getenv" from package "os" shouldn't be used`
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFoo(t *testing.T) {
+	time.Sleep(time.Second) // want `declaration "Sleep" from package "time" shouldn't be used`
+	func() {
+		time.Sleep(time.Second) // want `declaration "Sleep" from package "time" shouldn't be used`
+	}()
+	waitForIt()
+}
+
+func Testing(t *testing.T) {
+	time.Sleep(time.Second)
+}
+
+func BenchmarkFoo(b *testing.B) {
+	time.Sleep(time.Second) // want `declaration "Sleep" from package "time" shouldn't be used`
+}
+
+func FuzzFoo(f *testing.F) {
+	_ = strings.ToUpper("foo") // want `declaration "ToUpper" from package "strings" shouldn't be used`
+	time.Sleep(time.Second)
+}
+
+func ExampleFoo() {
+	_ = strings.ToUpper("foo") // want `declaration "ToUpper" from package "strings" shouldn't be used`
+}
+
+func waitForIt() {
+	time.Sleep(time.Second)
+	_ = strings.ToUpper("foo")
+}
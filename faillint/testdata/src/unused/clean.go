@@ -0,0 +1,7 @@
+//lint:file-ignore faillint nothing to suppress // want `unused faillint directive`
+
+package unused
+
+import "strings"
+
+var s = strings.ToUpper("foo")
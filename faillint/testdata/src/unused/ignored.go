@@ -0,0 +1,7 @@
+//lint:file-ignore faillint generated by hand
+
+package unused
+
+import "errors"
+
+var errFoo = errors.New("foo")
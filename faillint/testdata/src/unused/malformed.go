@@ -0,0 +1,7 @@
+//lint:file-ignore faillint only malformed directives // want `unused faillint directive`
+
+package unused
+
+var bar = "bar"
+
+//lint:ignore-end faillint // want `faillint:ignore-end directive without faillint:ignore-start directive`
@@ -0,0 +1,17 @@
+package unused
+
+import (
+	"errors"
+	"fmt"
+)
+
+func Foo() error {
+	//lint:ignore faillint errors are allowed here
+	_ = errors.New("foo")
+
+	//lint:ignore faillint nothing to suppress anymore // want `unused faillint directive`
+	_ = fmt.Sprint("foo")
+
+	//lint:ignore staticcheck not ours
+	return fmt.Errorf("foo") // want `declaration "Errorf" from package "fmt" shouldn't be used`
+}
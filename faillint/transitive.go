@@ -0,0 +1,67 @@
+package faillint
+
+import (
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// importsFact is exported for every package if any rule is transitive. Unlike
+// a package loaded from export data, it contains all imports of the package,
+// not only the ones its exported declarations refer to.
+type importsFact struct {
+	// Imports contains the import paths of the imports of the package.
+	Imports []string
+}
+
+func (*importsFact) AFact() {}
+
+func (f *importsFact) String() string {
+	return "imports: " + strings.Join(f.Imports, " ")
+}
+
+// exportImports exports an importsFact for the package.
+func exportImports(pass *analysis.Pass) {
+	pass.ExportPackageFact(&importsFact{Imports: importPaths(pass.Pkg)})
+}
+
+// importGraph returns the import paths of the imports of the package and of
+// its transitive dependencies by their import path. The imports of the
+// dependencies are taken from their importsFact, if they have one.
+func importGraph(pass *analysis.Pass) map[string][]string {
+	graph := make(map[string][]string)
+	var add func(pkg *types.Package)
+	add = func(pkg *types.Package) {
+		if _, ok := graph[pkg.Path()]; ok {
+			return
+		}
+		graph[pkg.Path()] = importPaths(pkg)
+		for _, imp := range pkg.Imports() {
+			add(imp)
+		}
+	}
+	add(pass.Pkg)
+
+	for _, f := range pass.AllPackageFacts() {
+		if fact, ok := f.Fact.(*importsFact); ok {
+			graph[f.Package.Path()] = fact.Imports
+		}
+	}
+	return graph
+}
+
+// importPaths returns the import paths of the imports of pkg.
+func importPaths(pkg *types.Package) []string {
+	paths := make([]string, 0, len(pkg.Imports()))
+	for _, imp := range pkg.Imports() {
+		paths = append(paths, imp.Path())
+	}
+	return paths
+}
+
+// isTransitive returns true if the rule matches the transitive dependencies
+// of imports as well.
+func isTransitive(r rule) bool {
+	return r.transitive
+}
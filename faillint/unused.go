@@ -0,0 +1,70 @@
+package faillint
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// fileIgnoreDirective returns the file-ignore directive of the comments, or
+// nil if there is none.
+func fileIgnoreDirective(pass *analysis.Pass, cgs []*ast.CommentGroup) *ast.Comment {
+	for _, cg := range cgs {
+		for _, c := range cg.List {
+			if isRangeDirective(c) {
+				continue
+			}
+			if opt, _ := parseDirective(pass, c); opt == fileIgnoreKey {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// suppressIgnoredFiles returns a copy of pass, which drops the problems
+// reported in the files with the given file-ignore directives and adds the
+// directives to used instead. It allows to analyze ignored files to find
+// unused file-ignore directives. Problems of faillint lint directives are
+// reported regardless, as they can't be ignored.
+func suppressIgnoredFiles(pass *analysis.Pass, ignored map[*token.File]*ast.Comment, used map[*ast.Comment]bool) *analysis.Pass {
+	directives := make(map[token.Pos]bool)
+	for _, f := range pass.Files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				if directiveFields(c.Text) != nil {
+					directives[c.Pos()] = true
+				}
+			}
+		}
+	}
+
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		if c, ok := ignored[pass.Fset.File(d.Pos)]; ok && !directives[d.Pos] {
+			used[c] = true
+			return
+		}
+		pass.Report(d)
+	}
+	return &p
+}
+
+// reportUnusedDirectives reports the faillint lint directives of files, which
+// aren't in used, as they didn't suppress any problem.
+func reportUnusedDirectives(pass *analysis.Pass, files []*ast.File, used map[*ast.Comment]bool) {
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				fields := directiveFields(c.Text)
+				if len(fields) < 3 || (fields[0] != ignoreKey && fields[0] != fileIgnoreKey && fields[0] != ignoreStartKey) {
+					continue
+				}
+				if !used[c] {
+					pass.Reportf(c.Pos(), "unused faillint directive")
+				}
+			}
+		}
+	}
+}
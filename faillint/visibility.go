@@ -0,0 +1,124 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// visibilityKey is the directive declaring the packages a package is visible
+// to, i.e: //faillint:visibility ./services/auth/... ./cmd/...
+const visibilityKey = "//faillint:visibility"
+
+// visibilityFact is exported for packages declaring their visibility.
+type visibilityFact struct {
+	// Patterns contains the patterns of the packages the package is visible
+	// to. Relative patterns are resolved against the module path.
+	Patterns []string
+}
+
+func (*visibilityFact) AFact() {}
+
+func (f *visibilityFact) String() string {
+	return "visibility: " + strings.Join(f.Patterns, " ")
+}
+
+// exportVisibility exports a visibilityFact for the package if any of its
+// files has a visibility directive before the package clause. Invalid
+// directives are reported.
+func exportVisibility(pass *analysis.Pass) {
+	var (
+		patterns []string
+		modPath  string
+	)
+	if mod := findModule(pass); mod != nil {
+		modPath = mod.Mod.Path
+	}
+	for _, file := range pass.Files {
+		for _, cg := range file.Comments {
+			if cg.Pos() > file.Package {
+				break
+			}
+			for _, c := range cg.List {
+				args, ok := strings.CutPrefix(c.Text, visibilityKey)
+				if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+					continue
+				}
+				fields := strings.Fields(args)
+				if len(fields) == 0 {
+					pass.Reportf(c.Pos(), "invalid visibility directive: missing patterns")
+					continue
+				}
+				for _, pat := range fields {
+					resolved, err := resolveVisibility(pat, modPath)
+					if err != nil {
+						pass.Reportf(c.Pos(), "invalid visibility directive: %v", err)
+						continue
+					}
+					patterns = append(patterns, resolved)
+				}
+			}
+		}
+	}
+	if len(patterns) > 0 {
+		pass.ExportPackageFact(&visibilityFact{Patterns: patterns})
+	}
+}
+
+// resolveVisibility resolves a pattern relative to the module, i.e:
+// ./cmd/..., into a full package pattern and validates it.
+func resolveVisibility(pat, modPath string) (string, error) {
+	negate := strings.HasPrefix(pat, "!")
+	resolved := strings.TrimPrefix(pat, "!")
+	if resolved == "." || strings.HasPrefix(resolved, "./") {
+		if modPath == "" {
+			return "", fmt.Errorf("relative pattern %q outside of a module", pat)
+		}
+		resolved = modPath + strings.TrimPrefix(resolved, ".")
+	}
+	if negate {
+		resolved = "!" + resolved
+	}
+	if _, err := compilePattern(resolved); err != nil {
+		return "", fmt.Errorf("pattern %q: %v", pat, err)
+	}
+	return resolved, nil
+}
+
+// reportVisibility reports the imports of f, whose imported package declared
+// a visibility that doesn't include the analyzed package. A package is always
+// visible to itself, its subpackages and its external tests.
+func reportVisibility(pass *analysis.Pass, ignores *ignoreDirectives, f *ast.File) {
+	pkgPath := strings.TrimSuffix(pass.Pkg.Path(), "_test")
+	for _, spec := range f.Imports {
+		impPath := importPath(spec)
+		pkg := importedPackage(pass.Pkg, impPath)
+		if pkg == nil {
+			continue
+		}
+		var fact visibilityFact
+		if !pass.ImportPackageFact(pkg, &fact) {
+			continue
+		}
+		if pkgPath == impPath || strings.HasPrefix(pkgPath, impPath+"/") {
+			continue
+		}
+
+		// The patterns have been validated by the exporting pass.
+		r := rule{}
+		for _, p := range fact.Patterns {
+			pat, _ := compilePattern(p)
+			r.in = append(r.in, pat)
+		}
+		if r.appliesTo(pass.Pkg) {
+			continue
+		}
+		if usageHasDirective(pass, ignores, spec, spec.Pos(), ignoreKey, directiveTargets(impPath, "", "")...) {
+			continue
+		}
+		pass.Reportf(spec.Path.Pos(), "package %q isn't visible to package %q, visible to: %s",
+			impPath, pass.Pkg.Path(), strings.Join(fact.Patterns, ", "))
+	}
+}
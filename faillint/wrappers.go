@@ -0,0 +1,298 @@
+package faillint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// wrapperFact is exported for functions that directly or transitively call
+// banned declarations.
+type wrapperFact struct {
+	// Calls contains the banned declarations called by the function.
+	Calls []wrappedCall
+}
+
+// wrappedCall is a banned declaration called by a wrapper.
+type wrappedCall struct {
+	// Chain contains the calls leading to the banned declaration, starting
+	// with the wrapper itself, i.e: ["util.die", "os.Exit"].
+	Chain []string
+
+	// Path and Name identify the banned declaration.
+	Path, Name string
+}
+
+func (*wrapperFact) AFact() {}
+
+func (f *wrapperFact) String() string {
+	chains := make([]string, 0, len(f.Calls))
+	for _, c := range f.Calls {
+		chains = append(chains, strings.Join(c.Chain, " -> "))
+	}
+	return "wraps: " + strings.Join(chains, "; ")
+}
+
+// add adds the call of the banned declaration to the fact, unless the fact
+// has a call of the same declaration already. It returns true if the call was
+// added.
+func (f *wrapperFact) add(c wrappedCall) bool {
+	for _, existing := range f.Calls {
+		if existing.Path == c.Path && existing.Name == c.Name {
+			return false
+		}
+	}
+	f.Calls = append(f.Calls, c)
+	return true
+}
+
+// exportWrappers exports a wrapperFact for every function of the package that
+// directly or transitively calls declarations banned by rules. Packages of
+// the standard library are skipped, as otherwise most of it would be
+// reported for commonly banned declarations, such as errors.New. So are files
+// with a file-ignore directive. The options restricting where a declaration is
+// banned, such as in or scope, apply to the calls of the wrappers only.
+func exportWrappers(pass *analysis.Pass, rules []rule, enclosing bool, used map[*ast.Comment]bool) {
+	if isStandardPackage(pass) {
+		return
+	}
+
+	type funcDecl struct {
+		decl    *ast.FuncDecl
+		file    *ast.File
+		ignores *ignoreDirectives
+	}
+	var (
+		funcs []*types.Func
+		decls = map[*types.Func]funcDecl{}
+		facts = map[*types.Func]*wrapperFact{}
+	)
+	for _, file := range pass.Files {
+		if fileIgnoreDirective(pass, file.Comments) != nil {
+			continue
+		}
+		ignores := newIgnoreDirectives(pass, file, enclosing, used)
+		for _, d := range file.Decls {
+			fd, ok := d.(*ast.FuncDecl)
+			if !ok || fd.Body == nil {
+				continue
+			}
+			fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			funcs = append(funcs, fn)
+			decls[fn] = funcDecl{fd, file, ignores}
+			facts[fn] = &wrapperFact{}
+		}
+	}
+
+	// wrap adds the call of the banned declaration of c by call in fn, unless
+	// the call is ignored.
+	wrap := func(fn *types.Func, call *ast.CallExpr, c wrappedCall) bool {
+		d := decls[fn]
+		if usageHasDirective(pass, d.ignores, call, call.Pos(), ignoreKey, directiveTargets(c.Path, c.Name, "")...) {
+			return false
+		}
+		return facts[fn].add(wrappedCall{
+			Chain: append([]string{qualifiedName(fn)}, c.Chain...),
+			Path:  c.Path,
+			Name:  c.Name,
+		})
+	}
+
+	// Find the functions calling banned declarations, either directly or
+	// through wrappers of other packages.
+	for _, fn := range funcs {
+		d := decls[fn]
+		ast.Inspect(d.decl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			callee := staticCallee(pass, call)
+			if callee == nil || callee.Pkg() == nil || callee.Pkg() == pass.Pkg {
+				return true
+			}
+			for _, r := range rules {
+				if r.matchesCall(pass, d.file, call, callee) {
+					wrap(fn, call, wrappedCall{
+						Chain: []string{qualifiedName(callee)},
+						Path:  callee.Pkg().Path(),
+						Name:  callee.Name(),
+					})
+					break
+				}
+			}
+			var imported wrapperFact
+			if pass.ImportObjectFact(callee, &imported) {
+				for _, c := range imported.Calls {
+					wrap(fn, call, c)
+				}
+			}
+			return true
+		})
+	}
+
+	// Propagate the facts to the functions calling them within the package,
+	// until there are no more changes.
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range funcs {
+			ast.Inspect(decls[fn].decl.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				callee := staticCallee(pass, call)
+				if callee == nil || callee == fn || facts[callee] == nil {
+					return true
+				}
+				for _, c := range facts[callee].Calls {
+					changed = wrap(fn, call, c) || changed
+				}
+				return true
+			})
+		}
+	}
+
+	for _, fn := range funcs {
+		if fact := facts[fn]; len(fact.Calls) > 0 {
+			pass.ExportObjectFact(fn, fact)
+		}
+	}
+}
+
+// reportWrappers reports the calls of functions of other packages in file,
+// which directly or transitively call declarations banned by rules. Calls of
+// functions, which are banned themselves, are reported by their rules only.
+func reportWrappers(pass *analysis.Pass, ignores *ignoreDirectives, file *ast.File, rules []rule) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		callee := staticCallee(pass, call)
+		if callee == nil || callee.Pkg() == nil || callee.Pkg() == pass.Pkg {
+			return true
+		}
+		var fact wrapperFact
+		if !pass.ImportObjectFact(callee, &fact) {
+			return true
+		}
+		for _, r := range rules {
+			if r.bansCall(pass, file, call, callee) {
+				return true
+			}
+		}
+
+		pos := calleePos(call)
+		for _, c := range fact.Calls {
+			for _, r := range rules {
+				if !r.bansDecl(c.Path, c.Name) || !r.appliesTo(pass.Pkg) || !r.inScope(pass, file, pos) {
+					continue
+				}
+				if usageHasDirective(pass, ignores, call, pos, ignoreKey, directiveTargets(c.Path, c.Name, r.id)...) {
+					break
+				}
+				msg := fmt.Sprintf("declaration %q from package %q shouldn't be used, called through: %s", c.Name, c.Path, strings.Join(c.Chain, " -> "))
+				if r.sugg != "" {
+					msg += fmt.Sprintf(", suggested: %q", r.sugg)
+				}
+				pass.Reportf(pos, msg)
+				break
+			}
+		}
+		return true
+	})
+}
+
+// staticCallee returns the function called by call, if it's known statically.
+// Functions and methods of instantiated generic types are resolved to their
+// declaration, which has the facts.
+func staticCallee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return nil
+	}
+	return callee.Origin()
+}
+
+// bansCall returns true if the call of callee in file is banned by the rule.
+func (r *rule) bansCall(pass *analysis.Pass, file *ast.File, call *ast.CallExpr, callee *types.Func) bool {
+	return r.appliesTo(pass.Pkg) && r.inScope(pass, file, calleePos(call)) && r.matchesCall(pass, file, call, callee)
+}
+
+// matchesCall returns true if the call of callee in file matches the
+// declarations of the rule and their argument constraints, regardless of
+// where the call is.
+func (r *rule) matchesCall(pass *analysis.Pass, file *ast.File, call *ast.CallExpr, callee *types.Func) bool {
+	if !r.bansDecl(callee.Pkg().Path(), callee.Name()) {
+		return false
+	}
+	pos := calleePos(call)
+	for _, d := range r.parsedDecls {
+		if d.matchName(callee.Name()) && d.matchCall(pass, file, pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// bansDecl returns true if the rule bans the package level declaration with
+// the given package path and name, regardless of how it's used.
+func (r *rule) bansDecl(pkgPath, name string) bool {
+	if r.construct != "" || r.imp == builtinPath || r.blank || len(r.parsedDecls) == 0 {
+		return false
+	}
+	if !r.matchesPath(pkgPath) {
+		return false
+	}
+	for _, d := range r.parsedDecls {
+		if d.matchName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// calleePos returns the position of the identifier of the called function
+// of call, i.e: Exit for os.Exit(1).
+func calleePos(call *ast.CallExpr) token.Pos {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.NamePos
+	case *ast.SelectorExpr:
+		return f.Sel.NamePos
+	}
+	return call.Pos()
+}
+
+// qualifiedName returns the name of fn qualified with its package name, i.e:
+// util.die or util.T.Method.
+func qualifiedName(fn *types.Func) string {
+	return fn.Pkg().Name() + "." + objName(fn)
+}
+
+// isStandardPackage reports whether the analyzed package is part of the
+// standard library. Standard library packages don't belong to a module and
+// the first element of their import path doesn't contain a dot.
+func isStandardPackage(pass *analysis.Pass) bool {
+	if pass.Module != nil && pass.Module.Path != "" {
+		return false
+	}
+	elem, _, _ := strings.Cut(pass.Pkg.Path(), "/")
+	return !strings.Contains(elem, ".")
+}
@@ -6,10 +6,8 @@ toolchain go1.24.0
 
 require (
 	dmitri.shuralyov.com/go/generated v0.0.0-20170818220700-b1254a446363
+	golang.org/x/mod v0.23.0
 	golang.org/x/tools v0.30.0
 )
 
-require (
-	golang.org/x/mod v0.23.0 // indirect
-	golang.org/x/sync v0.11.0 // indirect
-)
+require golang.org/x/sync v0.11.0 // indirect